@@ -2,6 +2,8 @@
 package clockwork
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"strconv"
 )
@@ -15,8 +17,10 @@ var Base32 = NewEncoding()
 
 // An Encoding is a radix 32 encoding/decoding scheme.
 type Encoding struct {
-	encode    [32]byte
-	decodeMap [256]byte
+	encode       [32]byte
+	decodeMap    [256]byte
+	constantTime bool
+	garbage      int // how to handle bytes that are not in the alphabet
 }
 
 // NewEncoding returns a new Encoding.
@@ -36,7 +40,7 @@ func NewEncoding() *Encoding {
 			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, /* 20-29 */
 			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, /* 30-39 */
 			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0, 1, /* 40-49 */
-			2, 3, 4, 5, 6, 7, 8, 9, 0, 0xFF, /* 50-59 */
+			2, 3, 4, 5, 6, 7, 8, 9, 0xFF, 0xFF, /* 50-59 */
 			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 10, 11, 12, 13, 14, /* 60-69 */
 			15, 16, 17, 1, 18, 19, 1, 20, 21, 0, /* 70-79 */
 			22, 23, 24, 25, 26, 0xFF, 27, 28, 29, 30, /* 80-89 */
@@ -64,6 +68,11 @@ func NewEncoding() *Encoding {
 // Encode encodes src using the encoding enc, writing
 // EncodedLen(len(src)) bytes to dst.
 func (enc *Encoding) Encode(dst, src []byte) {
+	if enc.constantTime {
+		encodeConstantTime(dst, src)
+		return
+	}
+
 	for len(src) >= 5 {
 		// Unpack 8x 5-bit source blocks into a 5 byte
 		// destination quantum
@@ -133,16 +142,20 @@ func (enc *Encoding) EncodedLen(n int) int {
 	return (n*8 + 4) / 5
 }
 
-type encoder struct {
+// An Encoder is a base32 stream encoder created by NewEncoder.
+// It can be reused with Reset, for example, in a sync.Pool.
+type Encoder struct {
 	err  error
 	enc  *Encoding
 	w    io.Writer
-	buf  [5]byte    // buffered data waiting to be encoded
-	nbuf int        // number of bytes in buf
-	out  [1024]byte // output buffer
+	ctx  context.Context // nil if the encoder is not created by NewEncoderContext
+	buf  [5]byte         // buffered data waiting to be encoded
+	nbuf int             // number of bytes in buf
+	out  [1024]byte      // output buffer
 }
 
-func (e *encoder) Write(p []byte) (n int, err error) {
+// Write encodes p and writes the complete quanta to the underlying writer.
+func (e *Encoder) Write(p []byte) (n int, err error) {
 	// based on https://github.com/golang/go/blob/ba9e10889976025ee1d027db6b1cad383ec56de8/src/encoding/base32/base32.go#L184
 
 	if e.err != nil {
@@ -162,7 +175,7 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 			return
 		}
 		e.enc.Encode(e.out[0:], e.buf[0:])
-		if _, e.err = e.w.Write(e.out[0:8]); e.err != nil {
+		if e.err = e.write(e.out[0:8]); e.err != nil {
 			return n, e.err
 		}
 		e.nbuf = 0
@@ -176,7 +189,7 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 			nn -= nn % 5
 		}
 		e.enc.Encode(e.out[0:], p[0:nn])
-		if _, e.err = e.w.Write(e.out[0 : nn/5*8]); e.err != nil {
+		if e.err = e.write(e.out[0 : nn/5*8]); e.err != nil {
 			return n, e.err
 		}
 		n += nn
@@ -192,24 +205,34 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 
 // Close flushes any pending output from the encoder.
 // It is an error to call Write after calling Close.
-func (e *encoder) Close() error {
+func (e *Encoder) Close() error {
 	// If there's anything left in the buffer, flush it out
 	if e.err == nil && e.nbuf > 0 {
 		e.enc.Encode(e.out[0:], e.buf[0:e.nbuf])
 		encodedLen := e.enc.EncodedLen(e.nbuf)
 		e.nbuf = 0
-		_, e.err = e.w.Write(e.out[0:encodedLen])
+		e.err = e.write(e.out[0:encodedLen])
 	}
 	return e.err
 }
 
+// Reset discards the encoder's state and makes it equivalent to
+// the result of its constructor with the same options, but writing to w instead.
+// Any partially written block that is not flushed by Close is discarded.
+func (e *Encoder) Reset(w io.Writer) {
+	e.err = nil
+	e.w = w
+	e.nbuf = 0
+}
+
 // NewEncoder returns a new base32 stream encoder. Data written to
 // the returned writer will be encoded using enc and then written to w.
 // Base32 encodings operate in 5-byte blocks; when finished
 // writing, the caller must Close the returned encoder to flush any
 // partially written blocks.
+// The returned writer is an *Encoder.
 func NewEncoder(enc *Encoding, w io.Writer) io.WriteCloser {
-	return &encoder{enc: enc, w: w}
+	return &Encoder{enc: enc, w: w}
 }
 
 /*
@@ -223,10 +246,21 @@ func (e CorruptInputError) Error() string {
 	return "illegal clockwork base32 data at input byte " + strconv.FormatInt(int64(e), 10)
 }
 
-// decode is like Decode but returns an additional 'end' value, which
-// indicates if end-of-message padding was encountered and thus any
-// additional data is an error.
+// decode is the implementation of Decode.
+// It handles the bytes that are not in the alphabet as configured by enc.
 func (enc *Encoding) decode(dst, src []byte) (n int, err error) {
+	if enc.garbage != garbageAbort {
+		return enc.decodeGarbage(dst, src)
+	}
+	return enc.decodeSymbols(dst, src)
+}
+
+// decodeSymbols decodes src that should consist of the symbols in the alphabet.
+func (enc *Encoding) decodeSymbols(dst, src []byte) (n int, err error) {
+	if enc.constantTime {
+		return decodeConstantTime(dst, src)
+	}
+
 	// Lift the nil check outside of the loop.
 	_ = enc.decodeMap
 
@@ -336,81 +370,191 @@ func (enc *Encoding) DecodeString(s string) ([]byte, error) {
 	return buf[:n], err
 }
 
+// CanonicalString returns the canonical spelling of the base32 string s:
+// letters in upper case, O as 0, and I and L as 1.
+// The symbols are mapped one by one, so the result has the same length as s.
+// It reports false if s has a byte that is not a symbol,
+// its length is not the encoded length of any data, or its padding bits are not zero.
+func (enc *Encoding) CanonicalString(s string) (string, bool) {
+	var extra uint // the number of padding bits in the last symbol
+	switch len(s) % 8 {
+	case 0:
+	case 2:
+		extra = 2
+	case 4:
+		extra = 4
+	case 5:
+		extra = 1
+	case 7:
+		extra = 3
+	default:
+		return "", false
+	}
+	for i := 0; i < len(s); i++ {
+		if enc.value(s[i]) == 0xFF {
+			return "", false
+		}
+	}
+	if extra > 0 && enc.value(s[len(s)-1])&(1<<extra-1) != 0 {
+		return "", false
+	}
+	buf := []byte(s)
+	for i, c := range buf {
+		buf[i] = enc.symbol(enc.value(c))
+	}
+	return string(buf), true
+}
+
 // DecodedLen returns the maximum length in bytes of the decoded data
 // corresponding to n bytes of base32-encoded data.
 func (enc *Encoding) DecodedLen(n int) int {
 	return n * 5 / 8
 }
 
-type decoder struct {
-	err    error
-	enc    *Encoding
-	r      io.Reader
-	buf    [1024]byte // leftover input
-	nbuf   int
-	out    []byte // leftover decoded output
-	outbuf [1024 / 8 * 5]byte
+// A Decoder is a base32 stream decoder created by NewDecoder.
+// It can be reused with Reset, for example, in a sync.Pool.
+type Decoder struct {
+	err       error
+	enc       *Encoding
+	r         io.Reader
+	buf       [1024]byte // leftover input
+	nbuf      int
+	out       []byte // leftover decoded output
+	outbuf    [1024 / 8 * 5]byte
+	nread     int64              // number of bytes read from r
+	nout      int64              // number of decoded bytes returned
+	errs      CorruptInputErrors // errors collected by CollectErrors
+	limits    Limits
+	segmented bool            // whether SegmentTerminator ends a partial quantum
+	ctx       context.Context // nil if the decoder has no context
 }
 
-func readEncodedData(r io.Reader, buf []byte) (n int, err error) {
-	for n < 1 && err == nil {
+// readEncodedData reads the encoded data from r into d.buf[d.nbuf:max]
+// until at least min bytes of symbols are buffered or an error occurs.
+func (d *Decoder) readEncodedData(min, max int) (err error) {
+	for d.nbuf < min && err == nil && d.terminator() < 0 {
+		if d.limits.MaxEncoded > 0 {
+			// read at most one byte over the limit to detect that it is exceeded.
+			if remain := d.limits.MaxEncoded - d.nread + 1; int64(max-d.nbuf) > remain {
+				max = d.nbuf + int(remain)
+			}
+		}
 		var nn int
-		nn, err = r.Read(buf[n:])
-		n += nn
+		nn, err = d.readSource(d.buf[d.nbuf:max])
+		if d.limits.MaxEncoded > 0 && d.nread+int64(nn) > d.limits.MaxEncoded {
+			nn = int(d.limits.MaxEncoded - d.nread)
+			err = &LimitError{Limit: d.limits.MaxEncoded}
+		}
+		offset := d.nread
+		d.nread += int64(nn)
+		if d.enc.garbage != garbageAbort {
+			raw := d.buf[d.nbuf : d.nbuf+nn]
+			var symbols []byte
+			keep := -1
+			if d.segmented {
+				keep = SegmentTerminator
+			}
+			symbols, d.errs = d.enc.stripGarbage(raw[:0], raw, offset, d.errs, keep)
+			nn = len(symbols)
+		}
+		d.nbuf += nn
 	}
-	return
+	if err == io.EOF && len(d.errs) > 0 {
+		err = d.errs
+	}
+	return err
 }
 
-func (d *decoder) Read(p []byte) (n int, err error) {
+// Read reads and decodes data from the underlying reader.
+func (d *Decoder) Read(p []byte) (n int, err error) {
+	if d.limits.MaxDecoded <= 0 {
+		return d.read(p)
+	}
+
+	remain := d.limits.MaxDecoded - d.nout
+	if remain <= 0 {
+		// check whether there is more data.
+		var buf [1]byte
+		if n, err := d.read(buf[:]); n == 0 {
+			return 0, err
+		}
+		d.out = nil
+		d.err = &LimitError{Limit: d.limits.MaxDecoded, Decoded: true}
+		return 0, d.err
+	}
+	if int64(len(p)) > remain {
+		p = p[:remain]
+	}
+	n, err = d.read(p)
+	d.nout += int64(n)
+	return n, err
+}
+
+func (d *Decoder) read(p []byte) (n int, err error) {
 	// based on https://github.com/golang/go/blob/ba9e10889976025ee1d027db6b1cad383ec56de8/src/encoding/base32/base32.go#L410
 
 	// Use leftover decoded output from last read.
 	if len(d.out) > 0 {
 		n = copy(p, d.out)
 		d.out = d.out[n:]
-		if len(d.out) == 0 {
+		if len(d.out) == 0 && d.nbuf == 0 {
 			return n, d.err
 		}
 		return n, nil
 	}
 
-	if d.err != nil {
+	if d.err != nil && d.nbuf == 0 {
 		return 0, d.err
 	}
 
 	// Read a chunk.
-	nn := len(p) / 5 * 8
-	if nn < 8 {
-		nn = 8
+	if d.err == nil {
+		nn := len(p) / 5 * 8
+		if nn < 8 {
+			nn = 8
+		}
+		if nn > len(d.buf) {
+			nn = len(d.buf)
+		}
+		d.err = d.readEncodedData(8, nn)
 	}
-	if nn > len(d.buf) {
-		nn = len(d.buf)
+
+	// Decode whole quanta, or all the rest at the end of the input or the segment.
+	nr, skip := d.nbuf/8*8, 0
+	if i := d.terminator(); i >= 0 {
+		nr, skip = i, 1
+	} else if d.err != nil {
+		nr = d.nbuf
 	}
-	nn, d.err = readEncodedData(d.r, d.buf[d.nbuf:nn])
-	d.nbuf += nn
 
 	// Decode chunk into p, or d.out and then p if p is too small.
-	nr := d.nbuf
-	nw := d.enc.DecodedLen(d.nbuf)
+	nw := d.enc.DecodedLen(nr)
 	if nw > len(p) {
-		nw, err = d.enc.decode(d.outbuf[0:], d.buf[0:nr])
+		nw, err = d.enc.decodeSymbols(d.outbuf[0:], d.buf[0:nr])
 		d.out = d.outbuf[0:nw]
 		n = copy(p, d.out)
 		d.out = d.out[n:]
 	} else {
-		n, err = d.enc.decode(p, d.buf[0:nr])
+		n, err = d.enc.decodeSymbols(p, d.buf[0:nr])
+	}
+	if pos, ok := err.(CorruptInputError); ok {
+		// convert the offset in the chunk to the offset in the stream.
+		err = pos + CorruptInputError(d.nread-int64(d.nbuf))
 	}
-	d.nbuf -= nr
+	d.nbuf -= nr + skip
 
 	for i := 0; i < d.nbuf; i++ {
-		d.buf[i] = d.buf[i+nr]
+		d.buf[i] = d.buf[i+nr+skip]
 	}
 
-	if err != nil && (d.err == nil || d.err == io.EOF) {
-		d.err = err
+	if err != nil {
+		d.nbuf = 0
+		if d.err == nil || d.err == io.EOF {
+			d.err = err
+		}
 	}
 
-	if len(d.out) > 0 {
+	if len(d.out) > 0 || d.nbuf > 0 {
 		// We cannot return all the decoded bytes to the caller in this
 		// invocation of Read, so we return a nil error to ensure that Read
 		// will be called again.  The error stored in d.err, if any, will be
@@ -421,9 +565,73 @@ func (d *decoder) Read(p []byte) (n int, err error) {
 	return n, d.err
 }
 
+// terminator returns the index of the first SegmentTerminator in the buffer,
+// or -1 if there is no terminator or the decoder is not segmented.
+func (d *Decoder) terminator() int {
+	if !d.segmented {
+		return -1
+	}
+	return bytes.IndexByte(d.buf[:d.nbuf], SegmentTerminator)
+}
+
+// Reset discards the decoder's state and makes it equivalent to
+// the result of its constructor with the same options, but reading from r instead.
+func (d *Decoder) Reset(r io.Reader) {
+	d.err = nil
+	d.r = r
+	d.nbuf = 0
+	d.out = nil
+	d.nread = 0
+	d.nout = 0
+	d.errs = nil
+}
+
 // NewDecoder constructs a new base32 stream decoder.
+// The returned reader is a *Decoder.
 func NewDecoder(enc *Encoding, r io.Reader) io.Reader {
-	return &decoder{enc: enc, r: r}
+	return &Decoder{enc: enc, r: r}
+}
+
+// DecoderOptions configures a decoder created by NewDecoderWithOptions.
+// The zero value is the same as NewDecoder.
+type DecoderOptions struct {
+	// Limits restricts the amount of data.
+	// Once the data exceeds a limit, the decoder returns a *LimitError.
+	// The data up to the limit is decoded before the error is returned.
+	Limits Limits
+
+	// Segmented indicates that the input is written by NewSegmentEncoder,
+	// and the decoder restarts quanta after each SegmentTerminator,
+	// as NewSegmentDecoder does.
+	Segmented bool
+
+	// Context stops the decoder when it is done.
+	// It is checked before each read from the underlying reader,
+	// and Read returns Context.Err() once it is done.
+	//
+	// A read blocked in the reader is interrupted only if the reader has
+	// a SetReadDeadline(time.Time) error method, like net.Conn and os.File;
+	// the deadline is set to the past and is not restored,
+	// because the previous deadline can't be read back.
+	// The data read before the interruption is decoded and returned before Context.Err().
+	// Otherwise, the read runs on another goroutine,
+	// and Read returns without waiting for it. The data of the read is discarded.
+	//
+	// Unless Context is never canceled, each read from the reader starts a goroutine
+	// and allocates, which is noticeable with small reads.
+	Context context.Context
+}
+
+// NewDecoderWithOptions constructs a new base32 stream decoder with opts.
+// The returned reader is a *Decoder.
+func NewDecoderWithOptions(enc *Encoding, r io.Reader, opts DecoderOptions) io.Reader {
+	return &Decoder{
+		enc:       enc,
+		r:         r,
+		limits:    opts.Limits,
+		segmented: opts.Segmented,
+		ctx:       opts.Context,
+	}
 }
 
 // grow increases the capacity of the byte slice.
@@ -2,9 +2,12 @@ package clockwork
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 type testCase struct {
@@ -117,6 +120,28 @@ func TestEncoder_Buffering(t *testing.T) {
 	}
 }
 
+func TestEncoder_Reset(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewEncoder(Base32, &buf).(*Encoder)
+	w.Write([]byte("discarded"))
+	for _, testCase := range testCasesEncode {
+		buf.Reset()
+		w.Reset(&buf)
+		if _, err := w.Write([]byte(testCase.plain)); err != nil {
+			t.Errorf("error while encoding %q: %v", testCase.plain, err)
+			continue
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("error while encoding %q: %v", testCase.plain, err)
+			continue
+		}
+		if buf.String() != testCase.encoded {
+			t.Errorf("encoded %q, expected %q, actual %q\n",
+				testCase.plain, testCase.encoded, buf.String())
+		}
+	}
+}
+
 var testCasesDecode = []testCase{
 	// from https://github.com/szktty/go-clockwork-base32/blob/c2cac4daa7ad2045089b943b377b12ac57e3254e/base32_test.go#L36-L44
 	{"foobar", "CSQPYRK1E8"},
@@ -237,6 +262,7 @@ var testCasesDecodeError = []struct {
 	{"u", 0},
 	{"CSQG*", 4},
 	{"CSQPYRK*", 7},
+	{"CSQ:", 3},
 }
 
 func TestDecode_Error(t *testing.T) {
@@ -270,6 +296,53 @@ func TestDecoder(t *testing.T) {
 	}
 }
 
+func TestDecoder_Reset(t *testing.T) {
+	r := NewDecoder(Base32, strings.NewReader("*")).(*Decoder)
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("want error, got nil")
+	}
+	for _, testCase := range testCasesDecode {
+		r.Reset(strings.NewReader(testCase.encoded))
+		plain, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("error while decoding %q: %v", testCase.encoded, err)
+		}
+		if !bytes.Equal(plain, []byte(testCase.plain)) {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.encoded, testCase.plain, plain)
+		}
+	}
+}
+
+func TestReset_Allocs(t *testing.T) {
+	w := NewEncoder(Base32, io.Discard).(*Encoder)
+	input := []byte(bigtest.plain)
+	allocs := testing.AllocsPerRun(100, func() {
+		w.Reset(io.Discard)
+		w.Write(input)
+		w.Close()
+	})
+	if allocs != 0 {
+		t.Errorf("encoder: want no allocations, got %v", allocs)
+	}
+
+	r := NewDecoder(Base32, nil).(*Decoder)
+	src := strings.NewReader(bigtest.encoded)
+	buf := make([]byte, 64)
+	allocs = testing.AllocsPerRun(100, func() {
+		src.Reset(bigtest.encoded)
+		r.Reset(src)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				break
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("decoder: want no allocations, got %v", allocs)
+	}
+}
+
 func TestDecoder_Buffering(t *testing.T) {
 	for bs := 1; bs <= 24; bs++ {
 		decoder := NewDecoder(Base32, strings.NewReader(bigtest.encoded))
@@ -357,3 +430,92 @@ func BenchmarkDecodeString(b *testing.B) {
 		Base32.DecodeString(data)
 	}
 }
+
+func TestDecoder_ShortReads(t *testing.T) {
+	readers := map[string]func(io.Reader) io.Reader{
+		"OneByteReader": iotest.OneByteReader,
+		"HalfReader":    iotest.HalfReader,
+		"DataErrReader": iotest.DataErrReader,
+	}
+	for name, fn := range readers {
+		decoder := NewDecoder(Base32, fn(strings.NewReader(bigtest.encoded)))
+		got, err := io.ReadAll(decoder)
+		if err != nil {
+			t.Errorf("%s: error while decoding %q: %v", name, bigtest.encoded, err)
+		}
+		if string(got) != bigtest.plain {
+			t.Errorf("%s: decoded %q, expected %q, actual %q\n",
+				name, bigtest.encoded, bigtest.plain, got)
+		}
+	}
+}
+
+func TestDecoder_Error(t *testing.T) {
+	input := strings.Repeat("0", 1000) + "*"
+	decoder := NewDecoder(Base32, iotest.HalfReader(strings.NewReader(input)))
+	_, err := io.ReadAll(decoder)
+	if err != CorruptInputError(1000) {
+		t.Errorf("want CorruptInputError(1000), got %v", err)
+	}
+}
+
+func TestNewDecoderWithOptions(t *testing.T) {
+	// all options can be combined, e.g. for untrusted uploads.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := DecoderOptions{
+		Limits:    Limits{MaxDecoded: 4},
+		Segmented: true,
+		Context:   ctx,
+	}
+	r := NewDecoderWithOptions(Base32, strings.NewReader("CSQPY=CSQPY="), opts)
+	got, err := io.ReadAll(r)
+	var lerr *LimitError
+	if !errors.As(err, &lerr) || !lerr.Decoded {
+		t.Errorf("want LimitError, got %v", err)
+	}
+	if string(got) != "foof" {
+		t.Errorf("want %q, got %q", "foof", got)
+	}
+
+	cancel()
+	r = NewDecoderWithOptions(Base32, strings.NewReader("CSQPY=CSQPY="), opts)
+	if _, err := io.ReadAll(r); err != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestCanonicalString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"", "", true},
+		{"CSQPY", "CSQPY", true},
+		{"csqpy", "CSQPY", true},
+		{"csqpyrkle8", "CSQPYRK1E8", true},
+		{"Oo1iIlL0", "00111110", true},
+		{"V0", "V0", true},
+		{"V1", "", false},    // non-zero padding bits
+		{"CSQPZ", "", false}, // non-zero padding bits
+		{"0", "", false},     // invalid length
+		{"CSQ", "", false},   // invalid length
+		{"CSQ*", "", false},  // invalid symbol
+		{"CSQU", "", false},  // invalid symbol
+	}
+	for _, tt := range tests {
+		got, ok := Base32.CanonicalString(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CanonicalString(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// canonical strings are the encodings of their decoded data.
+	for n := 0; n < 20; n++ {
+		encoded := Base32.EncodeToString(bytes.Repeat([]byte{0xA5}, n))
+		if got, ok := Base32.CanonicalString(strings.ToLower(encoded)); !ok || got != encoded {
+			t.Errorf("CanonicalString(%q) = %q, %v", strings.ToLower(encoded), got, ok)
+		}
+	}
+}
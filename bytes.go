@@ -0,0 +1,141 @@
+package clockwork
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Bytes is a byte slice that is formatted in Base32.
+// It implements fmt.Formatter, so it can be passed to fmt and log functions directly.
+type Bytes []byte
+
+// String returns the base32 encoding of b.
+func (b Bytes) String() string {
+	return Base32.EncodeToString(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Bytes) MarshalText() ([]byte, error) {
+	return Base32.AppendEncode(nil, b), nil
+}
+
+// AppendText implements encoding.TextAppender.
+func (b Bytes) AppendText(dst []byte) ([]byte, error) {
+	return Base32.AppendEncode(dst, b), nil
+}
+
+// AppendBinary implements encoding.BinaryAppender.
+// It appends b as is.
+func (b Bytes) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, b...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Bytes) UnmarshalText(text []byte) error {
+	data, err := Base32.AppendDecode((*b)[:0], text)
+	if err != nil {
+		return err
+	}
+	*b = data
+	return nil
+}
+
+// formatBufPool is the pool of the buffers of Format.
+// A buffer on the stack would escape through fmt.State.Write.
+var formatBufPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 64)
+		return &buf
+	},
+}
+
+// maxFormatBufSize is the maximum size of the buffers returned to formatBufPool.
+const maxFormatBufSize = 64 << 10
+
+// Format implements fmt.Formatter.
+// The verbs %s, %v and %q print the base32 encoding of b.
+// The precision truncates the encoded symbols, e.g. %.8s prints the first 8 symbols,
+// and the width pads the result with spaces, on the left unless the '-' flag is given.
+// It encodes b into a pooled buffer, so it doesn't allocate an intermediate string.
+// The other verbs, such as %x, print b as a []byte.
+func (b Bytes) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v', 'q':
+	default:
+		fmt.Fprintf(f, formatString(f, verb), []byte(b))
+		return
+	}
+
+	// encode only the bytes that the precision needs.
+	src := []byte(b)
+	prec, hasPrec := f.Precision()
+	if hasPrec && prec < Base32.EncodedLen(len(src)) {
+		src = src[:(prec*5+7)/8]
+	}
+	bufp := formatBufPool.Get().(*[]byte)
+	symbols := Base32.AppendEncode((*bufp)[:0], src)
+	if hasPrec && prec < len(symbols) {
+		symbols = symbols[:prec]
+	}
+	n := len(symbols)
+	if verb == 'q' {
+		n += 2
+	}
+
+	pad := 0
+	if width, ok := f.Width(); ok && width > n {
+		pad = width - n
+	}
+	if !f.Flag('-') {
+		writePadding(f, pad)
+	}
+	if verb == 'q' {
+		io.WriteString(f, `"`)
+	}
+	f.Write(symbols)
+	if verb == 'q' {
+		io.WriteString(f, `"`)
+	}
+	if f.Flag('-') {
+		writePadding(f, pad)
+	}
+
+	if cap(symbols) <= maxFormatBufSize {
+		*bufp = symbols[:0]
+		formatBufPool.Put(bufp)
+	}
+}
+
+// formatString returns the format directive of verb with the flags, width and precision in f.
+func formatString(f fmt.State, verb rune) string {
+	buf := make([]byte, 0, 16)
+	buf = append(buf, '%')
+	for _, c := range "+-# 0" {
+		if f.Flag(int(c)) {
+			buf = append(buf, byte(c))
+		}
+	}
+	if width, ok := f.Width(); ok {
+		buf = strconv.AppendInt(buf, int64(width), 10)
+	}
+	if prec, ok := f.Precision(); ok {
+		buf = append(buf, '.')
+		buf = strconv.AppendInt(buf, int64(prec), 10)
+	}
+	return string(buf) + string(verb)
+}
+
+const spaces = "                                "
+
+func writePadding(w io.Writer, n int) {
+	for n > 0 {
+		m := n
+		if m > len(spaces) {
+			m = len(spaces)
+		}
+		io.WriteString(w, spaces[:m])
+		n -= m
+	}
+}
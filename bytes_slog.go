@@ -0,0 +1,12 @@
+//go:build go1.21
+// +build go1.21
+
+package clockwork
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer.
+// It allocates the encoded string, because a slog.Value holds the string.
+func (b Bytes) LogValue() slog.Value {
+	return slog.StringValue(b.String())
+}
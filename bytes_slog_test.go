@@ -0,0 +1,38 @@
+//go:build go1.21
+// +build go1.21
+
+package clockwork
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestBytes_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("hello", "id", Bytes("foobar"))
+	want := "level=INFO msg=hello id=CSQPYRK1E8\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBytes_LogValueAllocs(t *testing.T) {
+	// LogValue allocates only the encoded string.
+	b := Bytes("foobar")
+	allocs := testing.AllocsPerRun(100, func() {
+		b.LogValue()
+	})
+	if allocs > 1 {
+		t.Errorf("got %f allocs, want at most 1", allocs)
+	}
+}
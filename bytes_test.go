@@ -0,0 +1,109 @@
+package clockwork
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestBytes_Format(t *testing.T) {
+	b := Bytes("foobar")
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "CSQPYRK1E8"},
+		{"%v", "CSQPYRK1E8"},
+		{"%q", `"CSQPYRK1E8"`},
+		{"%.4s", "CSQP"},
+		{"%.4q", `"CSQP"`},
+		{"%.20s", "CSQPYRK1E8"},
+		{"%12s", "  CSQPYRK1E8"},
+		{"%-12s|", "CSQPYRK1E8  |"},
+		{"%8.4s", "    CSQP"},
+		{"%-8.4s|", "CSQP    |"},
+		{"%14q", `  "CSQPYRK1E8"`},
+		{"%40.1s", "                                       C"},
+		{"%x", "666f6f626172"},
+		{"%X", "666F6F626172"},
+		{"% x", "66 6f 6f 62 61 72"},
+		{"%#x", "0x666f6f626172"},
+		{"%.2x", "666f"},
+		{"%14x|", "  666f6f626172|"},
+		{"%-14x|", "666f6f626172  |"},
+		{"%d", "[102 111 111 98 97 114]"},
+		{"%+v", "CSQPYRK1E8"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, b); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprint(Bytes(nil)); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+	long := make(Bytes, 100)
+	if got := fmt.Sprintf("%s", long); got != long.String() {
+		t.Errorf("got %q, want %q", got, long.String())
+	}
+}
+
+func TestBytes_FormatAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	// Bytes in an interface, as it is passed to fmt.
+	var v interface{} = Bytes("foobar")
+	formats := []string{"%s", "%.4s", "%q", "%12s", "%-12s"}
+	for _, format := range formats {
+		allocs := testing.AllocsPerRun(100, func() {
+			fmt.Fprintf(io.Discard, format, v)
+		})
+		if allocs != 0 {
+			t.Errorf("Fprintf(%q): got %f allocs, want 0", format, allocs)
+		}
+	}
+}
+
+func TestBytes_JSON(t *testing.T) {
+	data, err := json.Marshal(Bytes("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"CSQPYRK1E8"` {
+		t.Errorf("got %s", data)
+	}
+
+	var b Bytes
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "foobar" {
+		t.Errorf("got %q, want %q", b, "foobar")
+	}
+	if err := json.Unmarshal([]byte(`"CSQ*"`), &b); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestBytes_AppendText(t *testing.T) {
+	got, err := Bytes("foobar").AppendText([]byte("hash="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hash=CSQPYRK1E8" {
+		t.Errorf("got %q, want %q", got, "hash=CSQPYRK1E8")
+	}
+}
+
+func TestBytes_AppendBinary(t *testing.T) {
+	got, err := Bytes("foobar").AppendBinary([]byte("hash="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hash=foobar" {
+		t.Errorf("got %q, want %q", got, "hash=foobar")
+	}
+}
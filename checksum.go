@@ -0,0 +1,65 @@
+package clockwork
+
+import "errors"
+
+// ErrCheckSymbol is returned when the check symbol does not match the data.
+var ErrCheckSymbol = errors.New("clockwork: check symbol mismatch")
+
+// The check symbol is calculated by the Damm algorithm.
+// The quasigroup is x*y = 2x + y over GF(32), which detects
+// all single-symbol errors and all adjacent transposition errors.
+
+// mul2 multiplies x by 2 in GF(32) with the polynomial x^5 + x^2 + 1.
+func mul2(x byte) byte {
+	return (x<<1)&0x1F ^ (0x05 & -(x >> 4))
+}
+
+// checksum returns the interim digit of the Damm algorithm.
+func (enc *Encoding) checksum(src []byte) (byte, error) {
+	var interim byte
+	for i, c := range src {
+		v := enc.value(c)
+		if v == 0xFF {
+			return 0, CorruptInputError(i)
+		}
+		interim = mul2(interim) ^ v
+	}
+	return interim, nil
+}
+
+// CheckSymbol returns the check symbol of the base32 encoded data src.
+// If src contains invalid base32 data, it will return CorruptInputError.
+func (enc *Encoding) CheckSymbol(src []byte) (byte, error) {
+	interim, err := enc.checksum(src)
+	if err != nil {
+		return 0, err
+	}
+	return enc.symbol(mul2(interim)), nil
+}
+
+// AppendCheckSymbol appends the base32 encoded src and its check symbol to dst
+// and returns the extended buffer.
+func (enc *Encoding) AppendCheckSymbol(dst, src []byte) []byte {
+	start := len(dst)
+	dst = enc.AppendEncode(dst, src)
+	interim, _ := enc.checksum(dst[start:])
+	return append(dst, enc.symbol(mul2(interim)))
+}
+
+// VerifyCheckSymbol verifies that the last symbol of src is
+// the check symbol of the rest of src.
+// It returns ErrCheckSymbol if they don't match,
+// and CorruptInputError if src contains invalid base32 data.
+func (enc *Encoding) VerifyCheckSymbol(src []byte) error {
+	if len(src) == 0 {
+		return ErrCheckSymbol
+	}
+	interim, err := enc.checksum(src)
+	if err != nil {
+		return err
+	}
+	if interim != 0 {
+		return ErrCheckSymbol
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package clockwork
+
+import "testing"
+
+func TestCheckSymbol(t *testing.T) {
+	enc := NewEncoding()
+	for _, testCase := range testCasesEncode {
+		encoded := enc.AppendCheckSymbol(nil, []byte(testCase.plain))
+		if string(encoded[:len(encoded)-1]) != testCase.encoded {
+			t.Errorf("encoded %q, expected %q, actual %q\n",
+				testCase.plain, testCase.encoded, encoded[:len(encoded)-1])
+		}
+		c, err := enc.CheckSymbol([]byte(testCase.encoded))
+		if err != nil {
+			t.Errorf("error while calculating check symbol of %q: %v", testCase.encoded, err)
+		}
+		if c != encoded[len(encoded)-1] {
+			t.Errorf("unexpected check symbol: want %q, got %q", encoded[len(encoded)-1], c)
+		}
+		if err := enc.VerifyCheckSymbol(encoded); err != nil {
+			t.Errorf("error while verifying %q: %v", encoded, err)
+		}
+	}
+}
+
+func TestCheckSymbol_Aliases(t *testing.T) {
+	enc := NewEncoding()
+	c0, err := enc.CheckSymbol([]byte("AXQQEB10D5T20WK5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, err := enc.CheckSymbol([]byte("axqqebiod5t2owk5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c0 != c1 {
+		t.Errorf("check symbols of aliases differ: %q and %q", c0, c1)
+	}
+}
+
+func TestVerifyCheckSymbol_Error(t *testing.T) {
+	enc := NewEncoding()
+	if err := enc.VerifyCheckSymbol(nil); err != ErrCheckSymbol {
+		t.Errorf("want ErrCheckSymbol, got %v", err)
+	}
+	if err := enc.VerifyCheckSymbol([]byte("CSQ*")); err != CorruptInputError(3) {
+		t.Errorf("want CorruptInputError(3), got %v", err)
+	}
+}
+
+func TestVerifyCheckSymbol_DetectErrors(t *testing.T) {
+	enc := NewEncoding()
+	code := enc.AppendCheckSymbol(nil, []byte("foobar"))
+
+	// single substitutions
+	for i := range code {
+		for v := 0; v < 32; v++ {
+			c := enc.encode[v]
+			if c == code[i] {
+				continue
+			}
+			typo := append([]byte(nil), code...)
+			typo[i] = c
+			if enc.VerifyCheckSymbol(typo) == nil {
+				t.Errorf("substitution %q is not detected", typo)
+			}
+		}
+	}
+
+	// adjacent transpositions
+	for i := 0; i+1 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		typo := append([]byte(nil), code...)
+		typo[i], typo[i+1] = typo[i+1], typo[i]
+		if enc.VerifyCheckSymbol(typo) == nil {
+			t.Errorf("transposition %q is not detected", typo)
+		}
+	}
+}
+
+func TestMul2(t *testing.T) {
+	// mul2 must be a permutation of GF(32)
+	var seen [32]bool
+	for x := byte(0); x < 32; x++ {
+		y := mul2(x)
+		if y >= 32 {
+			t.Fatalf("mul2(%d) = %d is out of range", x, y)
+		}
+		if seen[y] {
+			t.Fatalf("mul2 is not a permutation: mul2(%d) = %d", x, y)
+		}
+		seen[y] = true
+	}
+}
@@ -0,0 +1,180 @@
+package clockwork
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math"
+)
+
+// codeGeneratorMaxAttempts is the number of candidates New tries before giving up.
+const codeGeneratorMaxAttempts = 1000
+
+// A CodeGenerator generates random codes encoded in Base32
+// that don't contain any word in a blocklist.
+//
+// The words are matched after decoding, so the aliases O, I and L in a word
+// match 0 and 1 in a code, and U, which is not in the alphabet, matches V.
+type CodeGenerator struct {
+	// Rand is the source of randomness.
+	// If nil, crypto/rand.Reader is used.
+	Rand io.Reader
+
+	length int
+
+	// the automaton that matches the words in the blocklist.
+	next    [][32]int
+	blocked []bool
+}
+
+// NewCodeGenerator returns a new CodeGenerator for the codes of length symbols.
+// The words in blocklist that contain characters other than symbols and their aliases are ignored.
+func NewCodeGenerator(length int, blocklist []string) *CodeGenerator {
+	g := &CodeGenerator{length: length}
+	g.compile(blocklist)
+	return g
+}
+
+// compile builds the Aho-Corasick automaton of the words.
+func (g *CodeGenerator) compile(words []string) {
+	g.next = [][32]int{{}}
+	g.blocked = []bool{false}
+
+	// build the trie. zero means no transition, because the root is never a child.
+WORDS:
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		vals := make([]byte, len(word))
+		for i := 0; i < len(word); i++ {
+			if vals[i] = blocklistValue(word[i]); vals[i] == 0xFF {
+				continue WORDS
+			}
+		}
+		state := 0
+		for _, v := range vals {
+			if g.next[state][v] == 0 {
+				g.next = append(g.next, [32]int{})
+				g.blocked = append(g.blocked, false)
+				g.next[state][v] = len(g.next) - 1
+			}
+			state = g.next[state][v]
+		}
+		g.blocked[state] = true
+	}
+
+	// fill the transitions with the failure links in breadth first order.
+	fail := make([]int, len(g.next))
+	queue := []int{0}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		g.blocked[state] = g.blocked[state] || g.blocked[fail[state]]
+		for v := 0; v < 32; v++ {
+			child := g.next[state][v]
+			if child == 0 {
+				if state != 0 {
+					g.next[state][v] = g.next[fail[state]][v]
+				}
+				continue
+			}
+			if state != 0 {
+				fail[child] = g.next[fail[state]][v]
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// blocklistValue returns the 5-bit value of c, treating U as V.
+// It returns 0xFF if c is not a valid symbol.
+func blocklistValue(c byte) byte {
+	if v := Base32.value(c); v != 0xFF {
+		return v
+	}
+	if c == 'U' || c == 'u' {
+		return Base32.value('V')
+	}
+	return 0xFF
+}
+
+func (g *CodeGenerator) rand() io.Reader {
+	if g.Rand != nil {
+		return g.Rand
+	}
+	return rand.Reader
+}
+
+// Blocked reports whether code contains any word in the blocklist.
+// Characters that are not symbols separate words.
+func (g *CodeGenerator) Blocked(code string) bool {
+	state := 0
+	for i := 0; i < len(code); i++ {
+		v := blocklistValue(code[i])
+		if v == 0xFF {
+			state = 0
+			continue
+		}
+		state = g.next[state][v]
+		if g.blocked[state] {
+			return true
+		}
+	}
+	return false
+}
+
+// New returns a new random code that is not blocked.
+// The codes are uniformly distributed over the codes that are not blocked.
+func (g *CodeGenerator) New() (string, error) {
+	buf := make([]byte, g.length)
+	for i := 0; i < codeGeneratorMaxAttempts; i++ {
+		if _, err := io.ReadFull(g.rand(), buf); err != nil {
+			return "", err
+		}
+		for j, b := range buf {
+			buf[j] = Base32.symbol(b & 0x1F)
+		}
+		if code := string(buf); !g.Blocked(code) {
+			return code, nil
+		}
+	}
+	return "", errors.New("clockwork: too many codes are blocked")
+}
+
+// Entropy returns the entropy of the codes generated by g in bits.
+func (g *CodeGenerator) Entropy() float64 {
+	return 5*float64(g.length) - g.EntropyLoss()
+}
+
+// EntropyLoss returns the number of bits of entropy lost by the blocklist,
+// compared with random codes of the same length.
+// It is +Inf if all codes are blocked.
+func (g *CodeGenerator) EntropyLoss() float64 {
+	// p[state] is the probability that a random code reaches the state without being blocked.
+	p := make([]float64, len(g.next))
+	q := make([]float64, len(g.next))
+	p[0] = 1
+	for i := 0; i < g.length; i++ {
+		for j := range q {
+			q[j] = 0
+		}
+		for state, prob := range p {
+			if prob == 0 {
+				continue
+			}
+			for v := 0; v < 32; v++ {
+				if next := g.next[state][v]; !g.blocked[next] {
+					q[next] += prob / 32
+				}
+			}
+		}
+		p, q = q, p
+	}
+
+	var total float64
+	for _, prob := range p {
+		total += prob
+	}
+	return -math.Log2(total)
+}
@@ -0,0 +1,102 @@
+package clockwork
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCodeGenerator_Blocked(t *testing.T) {
+	g := NewCodeGenerator(8, []string{"BOOB", "fuck", "ASS", "SHIT", "no-symbol", ""})
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"B00B0000", true},
+		{"0000BOOB", true},
+		{"00b00b00", true},
+		{"XXFVCKXX", true},
+		{"XXFUCKXX", true},
+		{"XXXXXA55", false},
+		{"XXXXXAS5", false},
+		{"XXASXSXX", false},
+		{"5H1TXXXX", false},
+		{"SHLTXXXX", true},
+		{"B00-B000", false},
+		{"XXXXXXXX", false},
+		{"ZZZ", false},
+	}
+	for _, tt := range tests {
+		if got := g.Blocked(tt.code); got != tt.want {
+			t.Errorf("Blocked(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	// overlapping words are found by the failure links.
+	g = NewCodeGenerator(8, []string{"ABCD", "BC"})
+	if !g.Blocked("XABCX") {
+		t.Error("BC in ABCX must be blocked")
+	}
+}
+
+func TestCodeGenerator_New(t *testing.T) {
+	g := NewCodeGenerator(4, []string{"A", "B", "C", "D"})
+	for i := 0; i < 1000; i++ {
+		code, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != 4 || g.Blocked(code) {
+			t.Fatalf("unexpected code: %q", code)
+		}
+	}
+
+	// all codes are blocked.
+	var all []string
+	for i := 0; i < 32; i++ {
+		all = append(all, string(Base32.encode[i]))
+	}
+	g = NewCodeGenerator(4, all)
+	if _, err := g.New(); err == nil {
+		t.Error("want error")
+	}
+	if loss := g.EntropyLoss(); !math.IsInf(loss, 1) {
+		t.Errorf("EntropyLoss() = %f, want +Inf", loss)
+	}
+
+	// deterministic source of randomness
+	g = NewCodeGenerator(4, []string{"00"})
+	g.Rand = bytes.NewReader([]byte{0, 0, 1, 2, 0, 1, 0, 1})
+	code, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "0101" {
+		t.Errorf("got %q, want %q", code, "0101")
+	}
+}
+
+func TestCodeGenerator_EntropyLoss(t *testing.T) {
+	blocklist := []string{"AB", "BA", "Z", "XYZ", "00"}
+	g := NewCodeGenerator(3, blocklist)
+
+	// count the codes that are not blocked by brute force.
+	count := 0
+	for i := 0; i < 32*32*32; i++ {
+		code := string([]byte{Base32.encode[i>>10], Base32.encode[i>>5&31], Base32.encode[i&31]})
+		if !g.Blocked(code) {
+			count++
+		}
+	}
+	want := 15 - math.Log2(float64(count))
+	if got := g.EntropyLoss(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyLoss() = %f, want %f", got, want)
+	}
+	if got := g.Entropy(); math.Abs(got-math.Log2(float64(count))) > 1e-9 {
+		t.Errorf("Entropy() = %f, want %f", got, math.Log2(float64(count)))
+	}
+
+	if got := NewCodeGenerator(10, nil).EntropyLoss(); got != 0 {
+		t.Errorf("EntropyLoss() = %f, want 0", got)
+	}
+}
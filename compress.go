@@ -0,0 +1,155 @@
+package clockwork
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Compression is a compression method applied before encoding.
+type Compression byte
+
+const (
+	// NoCompression writes the data as is.
+	NoCompression Compression = iota
+
+	// FlateCompression compresses the data with compress/flate.
+	FlateCompression
+
+	// GzipCompression compresses the data with compress/gzip.
+	GzipCompression
+
+	// ZlibCompression compresses the data with compress/zlib.
+	ZlibCompression
+)
+
+// compressionMarker is the first byte of the marker of compressed streams.
+// It is not in the alphabet, so raw streams never start with it.
+const compressionMarker = '$'
+
+func (c Compression) marker() (byte, bool) {
+	switch c {
+	case FlateCompression:
+		return 'F', true
+	case GzipCompression:
+		return 'G', true
+	case ZlibCompression:
+		return 'Z', true
+	}
+	return 0, false
+}
+
+type compressEncoder struct {
+	zw  io.WriteCloser
+	enc io.WriteCloser
+}
+
+// NewCompressEncoder returns a new base32 stream encoder that compresses the data by c before encoding.
+// The encoded stream starts with a marker "$F", "$G" or "$Z" that identifies the compression method,
+// unless c is NoCompression.
+// Closing the writer flushes the compressor and any partially written blocks.
+func NewCompressEncoder(enc *Encoding, w io.Writer, c Compression) (io.WriteCloser, error) {
+	if c == NoCompression {
+		return NewEncoder(enc, w), nil
+	}
+	m, ok := c.marker()
+	if !ok {
+		return nil, fmt.Errorf("clockwork: unknown compression %d", c)
+	}
+	if _, err := w.Write([]byte{compressionMarker, m}); err != nil {
+		return nil, err
+	}
+
+	e := NewEncoder(enc, w)
+	var zw io.WriteCloser
+	switch c {
+	case FlateCompression:
+		zw, _ = flate.NewWriter(e, flate.DefaultCompression)
+	case GzipCompression:
+		zw = gzip.NewWriter(e)
+	case ZlibCompression:
+		zw = zlib.NewWriter(e)
+	}
+	return &compressEncoder{zw: zw, enc: e}, nil
+}
+
+func (e *compressEncoder) Write(p []byte) (int, error) {
+	return e.zw.Write(p)
+}
+
+func (e *compressEncoder) Close() error {
+	if err := e.zw.Close(); err != nil {
+		return err
+	}
+	return e.enc.Close()
+}
+
+type compressDecoder struct {
+	enc *Encoding
+	r   io.Reader
+	err error
+}
+
+// NewCompressDecoder constructs a new base32 stream decoder that detects the compression marker
+// written by NewCompressEncoder and decompresses the data.
+// Streams without a marker are decoded as is.
+func NewCompressDecoder(enc *Encoding, r io.Reader) io.Reader {
+	return &compressDecoder{enc: enc, r: r}
+}
+
+func (d *compressDecoder) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if d.enc != nil {
+		if d.err = d.detect(); d.err != nil {
+			return 0, d.err
+		}
+	}
+	return d.r.Read(p)
+}
+
+// detect reads the marker and replaces d.r with the reader of the decompressed data.
+func (d *compressDecoder) detect() error {
+	enc := d.enc
+	d.enc = nil
+
+	br := bufio.NewReader(d.r)
+	marker, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if len(marker) == 0 || marker[0] != compressionMarker {
+		d.r = NewDecoder(enc, br)
+		return nil
+	}
+	if len(marker) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	kind := marker[1]
+	br.Discard(2)
+
+	dec := NewDecoder(enc, br)
+	switch kind {
+	case 'F':
+		d.r = flate.NewReader(dec)
+	case 'G':
+		zr, err := gzip.NewReader(dec)
+		if err != nil {
+			return err
+		}
+		d.r = zr
+	case 'Z':
+		zr, err := zlib.NewReader(dec)
+		if err != nil {
+			return err
+		}
+		d.r = zr
+	default:
+		return fmt.Errorf("clockwork: unknown compression marker %q", []byte{compressionMarker, kind})
+	}
+	return nil
+}
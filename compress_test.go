@@ -0,0 +1,101 @@
+package clockwork
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func encodeCompressed(t *testing.T, data []byte, c Compression) string {
+	t.Helper()
+	var buf strings.Builder
+	w, err := NewCompressEncoder(Base32, &buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCompress(t *testing.T) {
+	data := []byte(strings.Repeat(`{"name":"clockwork","tags":["base32","json"]},`, 100))
+	tests := []struct {
+		c      Compression
+		prefix string
+	}{
+		{NoCompression, ""},
+		{FlateCompression, "$F"},
+		{GzipCompression, "$G"},
+		{ZlibCompression, "$Z"},
+	}
+	for _, tt := range tests {
+		encoded := encodeCompressed(t, data, tt.c)
+		if !strings.HasPrefix(encoded, tt.prefix) {
+			t.Errorf("compression %d: got prefix %q, want %q", tt.c, encoded[:2], tt.prefix)
+		}
+		if tt.c != NoCompression && len(encoded) >= Base32.EncodedLen(len(data)) {
+			t.Errorf("compression %d: not compressed: %d bytes", tt.c, len(encoded))
+		}
+
+		readers := map[string]func(io.Reader) io.Reader{
+			"normal":  func(r io.Reader) io.Reader { return r },
+			"onebyte": iotest.OneByteReader,
+			"half":    iotest.HalfReader,
+		}
+		for name, wrap := range readers {
+			r := NewCompressDecoder(Base32, wrap(strings.NewReader(encoded)))
+			if err := iotest.TestReader(r, data); err != nil {
+				t.Errorf("compression %d, %s: %v", tt.c, name, err)
+			}
+		}
+	}
+}
+
+func TestCompress_Raw(t *testing.T) {
+	// streams written by NewEncoder are decoded as is.
+	var buf strings.Builder
+	w := NewEncoder(Base32, &buf)
+	w.Write([]byte("foobar"))
+	w.Close()
+	got, err := io.ReadAll(NewCompressDecoder(Base32, strings.NewReader(buf.String())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("got %q, want %q", got, "foobar")
+	}
+
+	got, err = io.ReadAll(NewCompressDecoder(Base32, strings.NewReader("")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestCompress_Error(t *testing.T) {
+	if _, err := NewCompressEncoder(Base32, io.Discard, 42); err == nil {
+		t.Error("want error for unknown compression")
+	}
+
+	encoded := encodeCompressed(t, bytes.Repeat([]byte("foobar"), 100), GzipCompression)
+	tests := []string{
+		"$",
+		"$X" + encoded[2:],
+		encoded[:len(encoded)/2],
+		encoded[:2] + "*" + encoded[3:],
+	}
+	for _, tt := range tests {
+		if _, err := io.ReadAll(NewCompressDecoder(Base32, strings.NewReader(tt))); err == nil {
+			t.Errorf("%q: want error", tt)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package clockwork
+
+// ConstantTime creates a new encoding identical to enc except that symbols
+// are mapped with branch-free arithmetic instead of table lookups,
+// so the memory access pattern of Encode and Decode does not depend on the data.
+// It is slower than the table-driven encoding; use it for secret material
+// such as API keys.
+func (enc Encoding) ConstantTime() *Encoding {
+	enc.constantTime = true
+	return &enc
+}
+
+// ctMask returns 0xFFFFFFFF if lo <= x <= hi, otherwise 0.
+func ctMask(x, lo, hi int32) int32 {
+	return ((lo - 1 - x) & (x - hi - 1)) >> 31
+}
+
+// ctGreaterEqual returns 1 if x >= y, otherwise 0.
+func ctGreaterEqual(x, y int32) int32 {
+	return int32(uint32(y-1-x) >> 31)
+}
+
+// encodeSymbol converts a 5-bit value into its symbol in constant time.
+func encodeSymbol(v byte) byte {
+	x := int32(v)
+	c := '0' + x
+	c += ctGreaterEqual(x, 10) * ('A' - '0' - 10)
+	c += ctGreaterEqual(x, 18) // skip I
+	c += ctGreaterEqual(x, 20) // skip L
+	c += ctGreaterEqual(x, 22) // skip O
+	c += ctGreaterEqual(x, 27) // skip U
+	return byte(c)
+}
+
+// decodeSymbol converts a symbol into its 5-bit value in constant time.
+// It returns 0xFF if c is not a valid symbol.
+func decodeSymbol(c byte) byte {
+	x := int32(c)
+	l := x | 0x20 // lower case letters
+	ret := int32(-1)
+	ret += ctMask(x, '0', '9') & (x - '0' + 1)
+	ret += ctMask(l, 'a', 'h') & (l - 'a' + 10 + 1)
+	ret += ctMask(l, 'j', 'k') & (l - 'j' + 18 + 1)
+	ret += ctMask(l, 'm', 'n') & (l - 'm' + 20 + 1)
+	ret += ctMask(l, 'p', 't') & (l - 'p' + 22 + 1)
+	ret += ctMask(l, 'v', 'z') & (l - 'v' + 27 + 1)
+
+	// aliases
+	ret += ctMask(l, 'o', 'o') & (0 + 1)
+	ret += (ctMask(l, 'i', 'i') | ctMask(l, 'l', 'l')) & (1 + 1)
+	return byte(ret)
+}
+
+// encodeConstantTime is the constant time version of Encode.
+func encodeConstantTime(dst, src []byte) {
+	for len(src) >= 5 {
+		val := uint64(src[0])<<32 | uint64(src[1])<<24 | uint64(src[2])<<16 | uint64(src[3])<<8 | uint64(src[4])
+		for i := 0; i < 8; i++ {
+			dst[i] = encodeSymbol(byte(val>>(35-5*uint(i))) & 0x1F)
+		}
+		src = src[5:]
+		dst = dst[8:]
+	}
+
+	// Add the remaining small block
+	if len(src) > 0 {
+		var val uint64
+		for i := 0; i < len(src); i++ {
+			val |= uint64(src[i]) << (32 - 8*uint(i))
+		}
+		size := uint(len(dst))
+		if size >= 8 {
+			size = 8
+		}
+		for i := uint(0); i < size; i++ {
+			dst[i] = encodeSymbol(byte(val>>(35-5*i)) & 0x1F)
+		}
+	}
+}
+
+// decodeConstantTime is the constant time version of decode.
+func decodeConstantTime(dst, src []byte) (n int, err error) {
+	olen := len(src)
+	for len(src) > 0 {
+		var dbuf [8]byte
+		size := len(src)
+		if size > 8 {
+			size = 8
+		}
+		for j := 0; j < size; j++ {
+			dbuf[j] = decodeSymbol(src[j])
+			if dbuf[j] == 0xFF {
+				return n, CorruptInputError(olen - len(src) + j)
+			}
+		}
+		src = src[size:]
+
+		val := uint64(dbuf[0])<<35 |
+			uint64(dbuf[1])<<30 |
+			uint64(dbuf[2])<<25 |
+			uint64(dbuf[3])<<20 |
+			uint64(dbuf[4])<<15 |
+			uint64(dbuf[5])<<10 |
+			uint64(dbuf[6])<<5 |
+			uint64(dbuf[7])
+
+		// the number of bytes in the quantum: 8 symbols make 5 bytes,
+		// and trailing symbols that don't fill a byte are padding.
+		m := size * 5 / 8
+		for i := 0; i < m; i++ {
+			dst[i] = byte(val >> (32 - 8*uint(i)))
+		}
+		n += m
+		dst = dst[m:]
+	}
+	return n, nil
+}
+
+// symbol returns the symbol of the 5-bit value v.
+func (enc *Encoding) symbol(v byte) byte {
+	if enc.constantTime {
+		return encodeSymbol(v)
+	}
+	return enc.encode[v]
+}
+
+// value returns the 5-bit value of the symbol c.
+// It returns 0xFF if c is not a valid symbol.
+func (enc *Encoding) value(c byte) byte {
+	if enc.constantTime {
+		return decodeSymbol(c)
+	}
+	return enc.decodeMap[c]
+}
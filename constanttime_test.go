@@ -0,0 +1,136 @@
+package clockwork
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeSymbol(t *testing.T) {
+	enc := NewEncoding()
+	for i := 0; i < 32; i++ {
+		if got, want := encodeSymbol(byte(i)), enc.encode[i]; got != want {
+			t.Errorf("encodeSymbol(%d): want %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestDecodeSymbol(t *testing.T) {
+	enc := NewEncoding()
+	for i := 0; i < 256; i++ {
+		if got, want := decodeSymbol(byte(i)), enc.decodeMap[i]; got != want {
+			t.Errorf("decodeSymbol(%q): want %d, got %d", i, want, got)
+		}
+	}
+}
+
+func TestEncode_ConstantTime(t *testing.T) {
+	enc := NewEncoding().ConstantTime()
+	for _, testCase := range testCasesEncode {
+		got := enc.EncodeToString([]byte(testCase.plain))
+		if got != testCase.encoded {
+			t.Errorf("encoded %q, expected %q, actual %q\n",
+				testCase.plain, testCase.encoded, got)
+		}
+	}
+}
+
+func TestDecode_ConstantTime(t *testing.T) {
+	enc := NewEncoding().ConstantTime()
+	for _, testCase := range testCasesDecode {
+		got, err := enc.DecodeString(testCase.encoded)
+		if err != nil {
+			t.Errorf("error while decoding %q: %v", testCase.encoded, err)
+		}
+		if string(got) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.encoded, testCase.plain, got)
+		}
+	}
+}
+
+func TestDecode_ConstantTimeError(t *testing.T) {
+	enc := NewEncoding().ConstantTime()
+	for _, testCase := range testCasesDecodeError {
+		_, err := enc.DecodeString(testCase.input)
+		switch err := err.(type) {
+		case CorruptInputError:
+			if int64(err) != testCase.pos {
+				t.Errorf("unexpected error position: want %d, got %d", testCase.pos, int64(err))
+			}
+		default:
+			t.Errorf("unexpected error type: want CorruptInputError, got %T", err)
+		}
+	}
+}
+
+func TestConstantTime_Compatible(t *testing.T) {
+	ct := Base32.ConstantTime()
+	raw := make([]byte, 256)
+	for i := range raw {
+		raw[i] = byte(i * 7)
+	}
+	for n := 0; n <= len(raw); n++ {
+		want := Base32.EncodeToString(raw[:n])
+		got := ct.EncodeToString(raw[:n])
+		if got != want {
+			t.Fatalf("%d bytes: encoded %q, want %q", n, got, want)
+		}
+
+		decoded, err := ct.DecodeString(got)
+		if err != nil {
+			t.Fatalf("%d bytes: error while decoding %q: %v", n, got, err)
+		}
+		if !bytes.Equal(decoded, raw[:n]) {
+			t.Fatalf("%d bytes: decoded %x, want %x", n, decoded, raw[:n])
+		}
+	}
+}
+
+func TestDecode_Colon(t *testing.T) {
+	// ':' was decoded as 0 by the table of the default encoding.
+	encodings := map[string]*Encoding{
+		"table":         Base32,
+		"constant-time": Base32.ConstantTime(),
+	}
+	for name, enc := range encodings {
+		for i := 0; i < 8; i++ {
+			src := []byte("CSQPYRK1")
+			src[i] = ':'
+			if _, err := enc.DecodeString(string(src)); err != CorruptInputError(i) {
+				t.Errorf("%s: DecodeString(%q): want CorruptInputError(%d), got %v", name, src, i, err)
+			}
+		}
+	}
+
+	// the table and the constant-time path accept the same bytes.
+	ct := Base32.ConstantTime()
+	for c := 0; c < 256; c++ {
+		src := string([]byte{'0', byte(c)})
+		_, want := Base32.DecodeString(src)
+		_, got := ct.DecodeString(src)
+		if got != want {
+			t.Errorf("%q: table returns %v, constant-time returns %v", src, want, got)
+		}
+	}
+}
+
+func BenchmarkEncode_ConstantTime(b *testing.B) {
+	enc := Base32.ConstantTime()
+	data := make([]byte, 8192)
+	buf := make([]byte, enc.EncodedLen(len(data)))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		enc.Encode(buf, data)
+	}
+}
+
+func BenchmarkDecode_ConstantTime(b *testing.B) {
+	enc := Base32.ConstantTime()
+	data := make([]byte, enc.EncodedLen(8192))
+	enc.Encode(data, make([]byte, 8192))
+	buf := make([]byte, 8192)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		enc.Decode(buf, data)
+	}
+}
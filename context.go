@@ -0,0 +1,163 @@
+package clockwork
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// NewEncoderContext is like NewEncoder but stops when ctx is done.
+// ctx is checked before each write to w, and Write and Close return ctx.Err() once it is done.
+//
+// A write blocked in w is interrupted when ctx is done only if w has
+// a SetWriteDeadline(time.Time) error method, like net.Conn and os.File;
+// the deadline of w is set to the past and is not restored,
+// because the previous deadline can't be read back.
+// Set a new deadline before using w again after ctx is done.
+// Otherwise, the write runs on another goroutine,
+// and Write returns without waiting for it. The write may still complete in the background,
+// so w must not be used by others after ctx is done.
+//
+// Unless ctx is never canceled, each write to w starts a goroutine
+// and allocates, which is noticeable with small writes.
+// The returned writer is an *Encoder.
+func NewEncoderContext(ctx context.Context, enc *Encoding, w io.Writer) io.WriteCloser {
+	return &Encoder{enc: enc, w: w, ctx: ctx}
+}
+
+// NewDecoderContext is like NewDecoder but stops when ctx is done.
+// It is the same as NewDecoderWithOptions with DecoderOptions.Context;
+// see DecoderOptions for how a blocked read is interrupted.
+// The returned reader is a *Decoder.
+func NewDecoderContext(ctx context.Context, enc *Encoding, r io.Reader) io.Reader {
+	return NewDecoderWithOptions(enc, r, DecoderOptions{Context: ctx})
+}
+
+// SetContext sets the context of the encoder, as NewEncoderContext does.
+// A nil ctx disables the context.
+// It can be used with Reset to reuse the encoder for another context.
+func (e *Encoder) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// SetContext sets the context of the decoder, as NewDecoderContext does.
+// A nil ctx disables the context.
+// It can be used with Reset to reuse the decoder for another context.
+func (d *Decoder) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// aLongTimeAgo is a deadline in the past that interrupts blocked I/O.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// interruptOnDone calls interrupt if ctx is done before stop is called.
+// stop waits for interrupt to return, and reports whether it was called.
+func interruptOnDone(ctx context.Context, interrupt func()) (stop func() bool) {
+	stopc := make(chan struct{})
+	result := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			interrupt()
+			result <- true
+		case <-stopc:
+			result <- false
+		}
+	}()
+	return func() bool {
+		close(stopc)
+		return <-result
+	}
+}
+
+type ioResult struct {
+	n   int
+	err error
+}
+
+// write writes p to the underlying writer.
+func (e *Encoder) write(p []byte) error {
+	if e.ctx == nil {
+		_, err := e.w.Write(p)
+		return err
+	}
+	if err := e.ctx.Err(); err != nil {
+		return err
+	}
+	if e.ctx.Done() == nil {
+		// ctx is never canceled.
+		_, err := e.w.Write(p)
+		return err
+	}
+
+	if w, ok := e.w.(writeDeadliner); ok {
+		stop := interruptOnDone(e.ctx, func() { w.SetWriteDeadline(aLongTimeAgo) })
+		_, err := e.w.Write(p)
+		if stop() {
+			return e.ctx.Err()
+		}
+		return err
+	}
+
+	// w can't be interrupted, and w.Write may continue after ctx is done,
+	// so it needs its own buffer.
+	buf := append([]byte(nil), p...)
+	ch := make(chan ioResult, 1)
+	go func(w io.Writer) {
+		n, err := w.Write(buf)
+		ch <- ioResult{n: n, err: err}
+	}(e.w)
+	select {
+	case res := <-ch:
+		return res.err
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+}
+
+// readSource reads data from the underlying reader into p.
+func (d *Decoder) readSource(p []byte) (int, error) {
+	if d.ctx == nil {
+		return d.r.Read(p)
+	}
+	if err := d.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if d.ctx.Done() == nil {
+		// ctx is never canceled.
+		return d.r.Read(p)
+	}
+
+	if r, ok := d.r.(readDeadliner); ok {
+		stop := interruptOnDone(d.ctx, func() { r.SetReadDeadline(aLongTimeAgo) })
+		n, err := d.r.Read(p)
+		if stop() {
+			// keep the data that was read before the interruption.
+			return n, d.ctx.Err()
+		}
+		return n, err
+	}
+
+	// r can't be interrupted, and r.Read may continue after ctx is done,
+	// so it needs its own buffer.
+	buf := make([]byte, len(p))
+	ch := make(chan ioResult, 1)
+	go func(r io.Reader) {
+		n, err := r.Read(buf)
+		ch <- ioResult{n: n, err: err}
+	}(d.r)
+	select {
+	case res := <-ch:
+		return copy(p, buf[:res.n]), res.err
+	case <-d.ctx.Done():
+		return 0, d.ctx.Err()
+	}
+}
@@ -0,0 +1,243 @@
+package clockwork
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingReader returns data, and then blocks until unblock is closed.
+type blockingReader struct {
+	data    io.Reader
+	unblock chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		<-r.unblock
+	}
+	return n, err
+}
+
+// blockingWriter blocks until unblock is closed.
+type blockingWriter struct {
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	return len(p), nil
+}
+
+func TestEncoderContext(t *testing.T) {
+	for _, testCase := range testCasesEncode {
+		var buf bytes.Buffer
+		w := NewEncoderContext(context.Background(), Base32, &buf)
+		if _, err := w.Write([]byte(testCase.plain)); err != nil {
+			t.Errorf("error while encoding %q: %v", testCase.plain, err)
+			continue
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("error while encoding %q: %v", testCase.plain, err)
+			continue
+		}
+		if buf.String() != testCase.encoded {
+			t.Errorf("encoded %q, expected %q, actual %q\n",
+				testCase.plain, testCase.encoded, buf.String())
+		}
+	}
+}
+
+func TestEncoderContext_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	bw := &blockingWriter{unblock: make(chan struct{})}
+	defer close(bw.unblock)
+
+	w := NewEncoderContext(ctx, Base32, bw)
+	if _, err := w.Write(make([]byte, 1000)); err != context.DeadlineExceeded {
+		t.Errorf("Write: want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := w.Close(); err != context.DeadlineExceeded {
+		t.Errorf("Close: want %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestDecoderContext(t *testing.T) {
+	for _, testCase := range testCasesDecode {
+		r := NewDecoderContext(context.Background(), Base32, strings.NewReader(testCase.encoded))
+		plain, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("error while decoding %q: %v", testCase.encoded, err)
+		}
+		if string(plain) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.encoded, testCase.plain, plain)
+		}
+	}
+}
+
+func TestDecoderContext_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	br := &blockingReader{
+		data:    strings.NewReader("CSQPYRK1"),
+		unblock: make(chan struct{}),
+	}
+	defer close(br.unblock)
+
+	r := NewDecoderContext(ctx, Base32, br)
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "fooba" {
+		t.Errorf("want %q, got %q", "fooba", buf[:n])
+	}
+
+	// the next read blocks until ctx is canceled.
+	time.AfterFunc(10*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Read(buf)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("want %v, got %v", context.Canceled, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read is not canceled")
+	}
+
+	if _, err := r.Read(buf); err != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestEncoderContext_Interrupt(t *testing.T) {
+	// net.Conn can be interrupted by the deadline.
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	w := NewEncoderContext(ctx, Base32, c1)
+	if _, err := w.Write(make([]byte, 1000)); err != context.Canceled {
+		t.Errorf("Write: want %v, got %v", context.Canceled, err)
+	}
+
+	// nothing is written after Write returns.
+	c2.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	var buf [1]byte
+	if n, err := c2.Read(buf[:]); n != 0 || err == nil {
+		t.Errorf("Read: got %d, %v", n, err)
+	}
+}
+
+func TestDecoderContext_Interrupt(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	r := NewDecoderContext(ctx, Base32, c1)
+	if _, err := r.Read(make([]byte, 64)); err != context.Canceled {
+		t.Errorf("Read: want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestSetContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	e := NewEncoderContext(ctx, Base32, &buf).(*Encoder)
+	if _, err := e.Write([]byte("foobar")); err != context.Canceled {
+		t.Errorf("Write: want %v, got %v", context.Canceled, err)
+	}
+	e.Reset(&buf)
+	e.SetContext(context.Background())
+	e.Write([]byte("foobar"))
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "CSQPYRK1E8" {
+		t.Errorf("want %q, got %q", "CSQPYRK1E8", buf.String())
+	}
+
+	d := NewDecoderContext(ctx, Base32, strings.NewReader("CSQPYRK1E8")).(*Decoder)
+	if _, err := d.Read(make([]byte, 64)); err != context.Canceled {
+		t.Errorf("Read: want %v, got %v", context.Canceled, err)
+	}
+	d.Reset(strings.NewReader("CSQPYRK1E8"))
+	d.SetContext(context.Background())
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", got)
+	}
+}
+
+func TestEncoderContext_Allocs(t *testing.T) {
+	// no goroutines are started for the contexts that are never canceled.
+	e := NewEncoderContext(context.Background(), Base32, io.Discard)
+	data := make([]byte, 5000)
+	allocs := testing.AllocsPerRun(100, func() {
+		e.Write(data)
+	})
+	if allocs != 0 {
+		t.Errorf("got %f allocs, want 0", allocs)
+	}
+}
+
+// cancelingReader cancels the context while it reads,
+// and returns its data once the read deadline is set.
+type cancelingReader struct {
+	data        string
+	cancel      context.CancelFunc
+	interrupted chan struct{}
+	deadline    time.Time
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	<-r.interrupted
+	return copy(p, r.data), nil
+}
+
+func (r *cancelingReader) SetReadDeadline(t time.Time) error {
+	r.deadline = t
+	close(r.interrupted)
+	return nil
+}
+
+func TestDecoderContext_InterruptData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cr := &cancelingReader{
+		data:        "CSQPYRK1",
+		cancel:      cancel,
+		interrupted: make(chan struct{}),
+	}
+	r := NewDecoderContext(ctx, Base32, cr)
+	got, err := io.ReadAll(r)
+	if err != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+	if string(got) != "fooba" {
+		t.Errorf("want %q, got %q", "fooba", got)
+	}
+	if !cr.deadline.Before(time.Now()) {
+		t.Errorf("the deadline is not in the past: %v", cr.deadline)
+	}
+}
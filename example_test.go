@@ -3,6 +3,8 @@ package clockwork_test
 import (
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/shogo82148/go-clockwork-base32"
 )
@@ -76,3 +78,59 @@ func ExampleNewEncoder() {
 	// Output:
 	// CSQPY032C5S0
 }
+
+func ExampleTokenGenerator() {
+	g := &clockwork.TokenGenerator{
+		Bits:        40,
+		Prefix:      "sk_",
+		CheckSymbol: true,
+		Rand:        strings.NewReader("hello"), // use the default crypto/rand.Reader in production
+	}
+	token, err := g.New()
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(token)
+
+	data, err := g.Parse(token)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Printf("%q\n", data)
+	// Output:
+	// sk_D1JPRV3F3
+	// "hello"
+}
+
+func ExampleEncoding_Suggest() {
+	code := clockwork.Base32.AppendCheckSymbol(nil, []byte("Hello, world!"))
+	fmt.Println(string(code))
+
+	// "U" is not a valid symbol; it is probably a typo of "V".
+	fmt.Println(clockwork.Base32.Suggest("91JPRU3F5GG7EVVJDHJ22M"))
+	// Output:
+	// 91JPRV3F5GG7EVVJDHJ22M
+	// [91JPRV3F5GG7EVVJDHJ22M]
+}
+
+func ExampleEncoder_Reset() {
+	pool := sync.Pool{
+		New: func() interface{} {
+			return clockwork.NewEncoder(clockwork.Base32, nil)
+		},
+	}
+
+	for _, msg := range []string{"foo", "bar"} {
+		encoder := pool.Get().(*clockwork.Encoder)
+		encoder.Reset(os.Stdout)
+		encoder.Write([]byte(msg))
+		encoder.Close()
+		fmt.Println()
+		pool.Put(encoder)
+	}
+	// Output:
+	// CSQPY
+	// C9GQ4
+}
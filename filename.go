@@ -0,0 +1,87 @@
+package clockwork
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidFilename is returned by DecodeFilename when the name was not produced by EncodeFilename.
+var ErrInvalidFilename = errors.New("clockwork: invalid filename")
+
+const (
+	// emptyFilename is the file name of the empty name,
+	// because the empty string is not a valid file name.
+	emptyFilename = "_"
+
+	// filenameContinue is appended to all components except the last one,
+	// so that a directory never has the same name as a file.
+	filenameContinue = '-'
+
+	// filenameComponentLen is the maximum number of symbols in a component.
+	// It is a multiple of 8, and the component with the continuation mark
+	// fits in the 255 byte limit of common file systems.
+	filenameComponentLen = 248
+)
+
+// EncodeFilename returns a file name that represents name, using Base32.
+// The result is safe on case-insensitive file systems.
+// Long names are split into slash-separated components of at most 249 bytes;
+// use filepath.FromSlash to convert the result into an OS path.
+// The empty name is represented by "_".
+//
+// The result never matches a reserved name on Windows, such as CON, NUL, COM1 and LPT1,
+// without any escape: the alphabet has no O, I, L and U,
+// and an encoding never has 3 symbols.
+func EncodeFilename(name []byte) string {
+	if len(name) == 0 {
+		return emptyFilename
+	}
+	symbols := Base32.EncodeToString(name)
+	var b strings.Builder
+	for {
+		n := len(symbols)
+		if n > filenameComponentLen {
+			n = filenameComponentLen
+		}
+		b.WriteString(symbols[:n])
+		symbols = symbols[n:]
+		if len(symbols) == 0 {
+			break
+		}
+		b.WriteByte(filenameContinue)
+		b.WriteByte('/')
+	}
+	return b.String()
+}
+
+// DecodeFilename returns the name represented by the file name s produced by EncodeFilename.
+func DecodeFilename(s string) ([]byte, error) {
+	if s == emptyFilename {
+		return []byte{}, nil
+	}
+	components := strings.Split(s, "/")
+	var symbols strings.Builder
+	for i, component := range components {
+		last := i == len(components)-1
+		if !last {
+			if component == "" || component[len(component)-1] != filenameContinue {
+				return nil, fmt.Errorf("%w: component %d has no continuation mark", ErrInvalidFilename, i)
+			}
+			component = component[:len(component)-1]
+		}
+		if component == "" || !last && len(component) != filenameComponentLen || len(component) > filenameComponentLen {
+			return nil, fmt.Errorf("%w: component %d has invalid length", ErrInvalidFilename, i)
+		}
+		symbols.WriteString(component)
+	}
+
+	name, err := Base32.DecodeString(symbols.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilename, err)
+	}
+	if EncodeFilename(name) != s {
+		return nil, fmt.Errorf("%w: not canonical", ErrInvalidFilename)
+	}
+	return name, nil
+}
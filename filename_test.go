@@ -0,0 +1,109 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// windowsReserved matches the device names that can't be used as file names on Windows.
+var windowsReserved = regexp.MustCompile(`(?i)^(CON|PRN|AUX|NUL|COM[0-9]|LPT[0-9])(\..*)?$`)
+
+func testFilenameRoundTrip(t *testing.T, name []byte) {
+	t.Helper()
+	encoded := EncodeFilename(name)
+	for _, component := range strings.Split(encoded, "/") {
+		if len(component) > 255 {
+			t.Fatalf("EncodeFilename(%x): component too long: %d", name, len(component))
+		}
+		if windowsReserved.MatchString(strings.TrimSuffix(component, "-")) {
+			t.Fatalf("EncodeFilename(%x): reserved component: %q", name, component)
+		}
+	}
+	decoded, err := DecodeFilename(encoded)
+	if err != nil {
+		t.Fatalf("DecodeFilename(%q): %v", encoded, err)
+	}
+	if !bytes.Equal(decoded, name) {
+		t.Fatalf("DecodeFilename(%q) = %x, want %x", encoded, decoded, name)
+	}
+}
+
+func TestFilename_Exhaustive(t *testing.T) {
+	testFilenameRoundTrip(t, []byte{})
+	for i := 0; i < 1<<8; i++ {
+		testFilenameRoundTrip(t, []byte{byte(i)})
+	}
+	for i := 0; i < 1<<16; i++ {
+		testFilenameRoundTrip(t, []byte{byte(i >> 8), byte(i)})
+	}
+}
+
+func TestFilename_Long(t *testing.T) {
+	for n := 0; n < 1000; n++ {
+		name := make([]byte, n)
+		for i := range name {
+			name[i] = byte(i*31 + n)
+		}
+		testFilenameRoundTrip(t, name)
+	}
+}
+
+func TestEncodeFilename_Reserved(t *testing.T) {
+	// the last component never has 3 symbols, so it is never CON, PRN, AUX or NUL.
+	for n := 1; n < 1000; n++ {
+		if l := Base32.EncodedLen(n) % filenameComponentLen; l == 3 {
+			t.Errorf("EncodedLen(%d) has a component of 3 symbols", n)
+		}
+	}
+	// COMn and LPTn have letters that are not in the alphabet.
+	for _, c := range "OLIU" {
+		if bytes.IndexByte(Base32.encode[:], byte(c)) >= 0 {
+			t.Errorf("%c is in the alphabet", c)
+		}
+	}
+}
+
+func TestEncodeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "_"},
+		{"foobar", "CSQPYRK1E8"},
+		{strings.Repeat("\x00", 155), strings.Repeat("0", 248)},
+		{strings.Repeat("\x00", 156), strings.Repeat("0", 248) + "-/00"},
+	}
+	for _, tt := range tests {
+		if got := EncodeFilename([]byte(tt.name)); got != tt.want {
+			t.Errorf("EncodeFilename(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeFilename_Error(t *testing.T) {
+	tests := []string{
+		"",
+		"_CSQPYRK1E8",
+		"csqpyrk1e8",
+		"CSQPYRK1E9",
+		"CSQPYRK1E8-",
+		"CSQPYRK1E8-/00",
+		"CSQPYRK1E8/00",
+		strings.Repeat("0", 248) + "/00",
+		strings.Repeat("0", 248) + "-/",
+		strings.Repeat("0", 248) + "-/_",
+		"_CON",
+		"__",
+		"CON",
+		"COM1",
+		"CSQ*",
+	}
+	for _, tt := range tests {
+		if _, err := DecodeFilename(tt); !errors.Is(err, ErrInvalidFilename) {
+			t.Errorf("DecodeFilename(%q): unexpected error: %v", tt, err)
+		}
+	}
+}
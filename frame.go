@@ -0,0 +1,214 @@
+package clockwork
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// FrameChecksum is the checksum algorithm of a frame.
+type FrameChecksum byte
+
+const (
+	// FrameCRC32C is CRC-32 with the Castagnoli polynomial.
+	FrameCRC32C FrameChecksum = 1
+
+	// FrameSHA256 is SHA-256.
+	FrameSHA256 FrameChecksum = 2
+)
+
+const (
+	frameVersion    = 1
+	frameHeaderSize = 1 + 1 + 8 // version, checksum, length
+)
+
+var (
+	// ErrFrameHeader is returned when the header of a frame is malformed or unsupported.
+	ErrFrameHeader = errors.New("clockwork: invalid frame header")
+
+	// ErrFrameTruncated is returned when a frame ends before its length and trailer.
+	ErrFrameTruncated = errors.New("clockwork: truncated frame")
+
+	// ErrFrameCorrupt is returned when the checksum of a frame doesn't match,
+	// or a frame is followed by extra data.
+	ErrFrameCorrupt = errors.New("clockwork: corrupt frame")
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+func (sum FrameChecksum) new() (hash.Hash, bool) {
+	switch sum {
+	case FrameCRC32C:
+		return crc32.New(crc32cTable), true
+	case FrameSHA256:
+		return sha256.New(), true
+	}
+	return nil, false
+}
+
+// A frame is a base32 stream of the header, the data and the trailer.
+// The header has the format version, the checksum algorithm and the length of the data
+// in big endian, and the trailer is the checksum of the data.
+
+type frameWriter struct {
+	enc    io.WriteCloser
+	h      hash.Hash
+	remain int64
+	err    error
+	closed bool
+}
+
+// NewFrameWriter returns a writer that writes a frame of length bytes to w.
+// The data written to it are encoded by enc, and the checksum is computed by sum.
+// Close writes the trailer and fails if fewer than length bytes were written.
+func NewFrameWriter(enc *Encoding, w io.Writer, length int64, sum FrameChecksum) (io.WriteCloser, error) {
+	h, ok := sum.new()
+	if !ok {
+		return nil, fmt.Errorf("clockwork: unknown frame checksum %d", sum)
+	}
+	if length < 0 {
+		return nil, errors.New("clockwork: negative frame length")
+	}
+
+	var header [frameHeaderSize]byte
+	header[0] = frameVersion
+	header[1] = byte(sum)
+	binary.BigEndian.PutUint64(header[2:], uint64(length))
+	e := NewEncoder(enc, w)
+	if _, err := e.Write(header[:]); err != nil {
+		return nil, err
+	}
+	return &frameWriter{enc: e, h: h, remain: length}, nil
+}
+
+func (w *frameWriter) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, errors.New("clockwork: write to closed frame")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	var over bool
+	if int64(len(p)) > w.remain {
+		p = p[:w.remain]
+		over = true
+	}
+	n, w.err = w.enc.Write(p)
+	w.h.Write(p[:n])
+	w.remain -= int64(n)
+	if w.err == nil && over {
+		w.err = errors.New("clockwork: write exceeds frame length")
+	}
+	return n, w.err
+}
+
+func (w *frameWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	if w.remain != 0 {
+		return fmt.Errorf("clockwork: frame is %d bytes shorter than its length", w.remain)
+	}
+	if _, err := w.enc.Write(w.h.Sum(nil)); err != nil {
+		return err
+	}
+	return w.enc.Close()
+}
+
+type frameReader struct {
+	dec    io.Reader
+	h      hash.Hash
+	remain int64
+	err    error
+}
+
+// NewFrameReader returns a reader that decodes a frame from r.
+// It verifies the length and the checksum of the data before it returns io.EOF.
+func NewFrameReader(enc *Encoding, r io.Reader) io.Reader {
+	return &frameReader{dec: NewDecoder(enc, r), remain: -1}
+}
+
+func (r *frameReader) Read(p []byte) (n int, err error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.h == nil {
+		if r.err = r.readHeader(); r.err != nil {
+			return 0, r.err
+		}
+	}
+	if r.remain == 0 {
+		if r.err = r.readTrailer(); r.err == nil {
+			r.err = io.EOF
+		}
+		return 0, r.err
+	}
+
+	if int64(len(p)) > r.remain {
+		p = p[:r.remain]
+	}
+	n, err = r.dec.Read(p)
+	r.h.Write(p[:n])
+	r.remain -= int64(n)
+	if err == io.EOF {
+		err = ErrFrameTruncated
+	}
+	r.err = err
+	return n, err
+}
+
+func (r *frameReader) readHeader() error {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r.dec, header[:]); err != nil {
+		return truncated(err)
+	}
+	if header[0] != frameVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrFrameHeader, header[0])
+	}
+	h, ok := FrameChecksum(header[1]).new()
+	if !ok {
+		return fmt.Errorf("%w: unknown checksum %d", ErrFrameHeader, header[1])
+	}
+	length := binary.BigEndian.Uint64(header[2:])
+	if int64(length) < 0 {
+		return fmt.Errorf("%w: length %d overflows", ErrFrameHeader, length)
+	}
+	r.h = h
+	r.remain = int64(length)
+	return nil
+}
+
+func (r *frameReader) readTrailer() error {
+	want := make([]byte, r.h.Size())
+	if _, err := io.ReadFull(r.dec, want); err != nil {
+		return truncated(err)
+	}
+	if !bytes.Equal(r.h.Sum(nil), want) {
+		return fmt.Errorf("%w: checksum mismatch", ErrFrameCorrupt)
+	}
+	var extra [1]byte
+	if _, err := io.ReadFull(r.dec, extra[:]); err != io.EOF {
+		if err == nil {
+			return fmt.Errorf("%w: extra data after trailer", ErrFrameCorrupt)
+		}
+		return err
+	}
+	return nil
+}
+
+// truncated converts the errors of io.ReadFull on a short frame into ErrFrameTruncated.
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrFrameTruncated
+	}
+	return err
+}
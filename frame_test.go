@@ -0,0 +1,131 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func encodeFrame(t *testing.T, data []byte, sum FrameChecksum) string {
+	t.Helper()
+	var buf strings.Builder
+	w, err := NewFrameWriter(Base32, &buf, int64(len(data)), sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestFrame(t *testing.T) {
+	for _, sum := range []FrameChecksum{FrameCRC32C, FrameSHA256} {
+		for _, n := range []int{0, 1, 5, 100, 5000} {
+			data := make([]byte, n)
+			for i := range data {
+				data[i] = byte(i * 11)
+			}
+			encoded := encodeFrame(t, data, sum)
+			r := NewFrameReader(Base32, iotest.OneByteReader(strings.NewReader(encoded)))
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("checksum %d, length %d: %v", sum, n, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("checksum %d, length %d: data mismatch", sum, n)
+			}
+		}
+	}
+}
+
+func TestFrameWriter_Error(t *testing.T) {
+	if _, err := NewFrameWriter(Base32, io.Discard, 10, 0); err == nil {
+		t.Error("want error for unknown checksum")
+	}
+	if _, err := NewFrameWriter(Base32, io.Discard, -1, FrameCRC32C); err == nil {
+		t.Error("want error for negative length")
+	}
+
+	w, err := NewFrameWriter(Base32, io.Discard, 3, FrameCRC32C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Write([]byte("foobar")); n != 3 || err == nil {
+		t.Errorf("Write() = %d, %v", n, err)
+	}
+
+	w, err = NewFrameWriter(Base32, io.Discard, 10, FrameCRC32C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("foo"))
+	if err := w.Close(); err == nil {
+		t.Error("want error for short frame")
+	}
+}
+
+func TestFrameReader_Error(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog.")
+	encoded := encodeFrame(t, data, FrameSHA256)
+
+	read := func(s string) error {
+		_, err := io.ReadAll(NewFrameReader(Base32, strings.NewReader(s)))
+		return err
+	}
+
+	// truncated at every quantum
+	for i := 0; i < len(encoded); i += 8 {
+		if err := read(encoded[:i]); !errors.Is(err, ErrFrameTruncated) {
+			t.Errorf("truncated at %d: unexpected error: %v", i, err)
+		}
+	}
+
+	// corrupted data
+	corrupted := []byte(encoded)
+	corrupted[30] = Base32.encode[(Base32.decodeMap[corrupted[30]]+1)%32]
+	if err := read(string(corrupted)); !errors.Is(err, ErrFrameCorrupt) {
+		t.Errorf("corrupted: unexpected error: %v", err)
+	}
+
+	// extra data
+	extra := Base32.EncodeToString(append(mustDecode(t, encoded), 0))
+	if err := read(extra); !errors.Is(err, ErrFrameCorrupt) {
+		t.Errorf("extra: unexpected error: %v", err)
+	}
+
+	// unsupported version
+	raw := mustDecode(t, encoded)
+	raw[0] = 2
+	if err := read(Base32.EncodeToString(raw)); !errors.Is(err, ErrFrameHeader) {
+		t.Errorf("version: unexpected error: %v", err)
+	}
+
+	// unknown checksum
+	raw = mustDecode(t, encoded)
+	raw[1] = 3
+	if err := read(Base32.EncodeToString(raw)); !errors.Is(err, ErrFrameHeader) {
+		t.Errorf("checksum: unexpected error: %v", err)
+	}
+
+	// invalid symbol
+	var cerr CorruptInputError
+	if err := read(encoded[:20] + "*" + encoded[21:]); !errors.As(err, &cerr) || cerr != 20 {
+		t.Errorf("invalid symbol: unexpected error: %v", err)
+	}
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := Base32.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
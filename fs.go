@@ -0,0 +1,184 @@
+package clockwork
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// FS is a file system that decodes the base32 encoded files in another file system.
+// Sizes of regular files are reported as their decoded lengths.
+type FS struct {
+	// FS is the underlying file system.
+	FS fs.FS
+
+	// Encoding is the encoding of the files. If nil, Base32 is used.
+	Encoding *Encoding
+
+	// EncodedNames reports whether the names in FS are encoded too.
+	// If true, each element of a path is encoded by EncodeFilename before it is opened,
+	// and entries whose names are not produced by EncodeFilename are skipped.
+	// The elements must be at most 155 bytes long, so that each encoded name
+	// is a single path element; opening a longer name fails with fs.ErrInvalid.
+	EncodedNames bool
+}
+
+// fsMaxNameLen is the maximum length of a name in FS,
+// whose encoding fits in a single component of EncodeFilename.
+const fsMaxNameLen = filenameComponentLen / 8 * 5
+
+// NewFS returns a file system that decodes the contents of the files in fsys.
+func NewFS(enc *Encoding, fsys fs.FS) *FS {
+	return &FS{FS: fsys, Encoding: enc}
+}
+
+func (fsys *FS) encoding() *Encoding {
+	if fsys.Encoding != nil {
+		return fsys.Encoding
+	}
+	return Base32
+}
+
+// Open implements fs.FS.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	encoded, err := fsys.encodePath(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	f, err := fsys.FS.Open(encoded)
+	if err != nil {
+		if e, ok := err.(*fs.PathError); ok {
+			e.Path = name
+		}
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	file := &file{fsys: fsys, f: f, info: fsys.fileInfo(info, path.Base(name))}
+	if info.IsDir() {
+		return &dir{file}, nil
+	}
+	file.r = NewDecoder(fsys.encoding(), f)
+	return file, nil
+}
+
+func (fsys *FS) encodePath(name string) (string, error) {
+	if !fsys.EncodedNames || name == "." {
+		return name, nil
+	}
+	elems := strings.Split(name, "/")
+	for i, elem := range elems {
+		if len(elem) > fsMaxNameLen {
+			return "", fmt.Errorf("%w: name longer than %d bytes", fs.ErrInvalid, fsMaxNameLen)
+		}
+		elems[i] = EncodeFilename([]byte(elem))
+	}
+	return strings.Join(elems, "/"), nil
+}
+
+// decodeName returns the decoded name of an entry.
+// It reports false if the entry should be skipped.
+func (fsys *FS) decodeName(name string) (string, bool) {
+	if !fsys.EncodedNames {
+		return name, true
+	}
+	b, err := DecodeFilename(name)
+	if err != nil {
+		return "", false
+	}
+	decoded := string(b)
+	if decoded == "." || strings.Contains(decoded, "/") || !fs.ValidPath(decoded) {
+		return "", false
+	}
+	return decoded, true
+}
+
+func (fsys *FS) fileInfo(info fs.FileInfo, name string) fs.FileInfo {
+	if !fsys.EncodedNames {
+		name = info.Name()
+	}
+	size := info.Size()
+	if info.Mode().IsRegular() {
+		size = decodedLen64(size)
+	}
+	return &fileInfo{FileInfo: info, name: name, size: size}
+}
+
+type fileInfo struct {
+	fs.FileInfo
+	name string
+	size int64
+}
+
+func (info *fileInfo) Name() string { return info.name }
+func (info *fileInfo) Size() int64  { return info.size }
+
+type dirEntry struct {
+	fs.DirEntry
+	fsys *FS
+	name string
+}
+
+func (e *dirEntry) Name() string { return e.name }
+
+func (e *dirEntry) Info() (fs.FileInfo, error) {
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return e.fsys.fileInfo(info, e.name), nil
+}
+
+type file struct {
+	fsys *FS
+	f    fs.File
+	r    io.Reader
+	info fs.FileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Read(p []byte) (int, error) { return f.r.Read(p) }
+func (f *file) Close() error               { return f.f.Close() }
+
+type dir struct {
+	*file
+}
+
+func (d *dir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rd, ok := d.f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: d.info.Name(), Err: fs.ErrInvalid}
+	}
+	var list []fs.DirEntry
+	for {
+		m := -1
+		if n > 0 {
+			m = n - len(list)
+		}
+		entries, err := rd.ReadDir(m)
+		for _, e := range entries {
+			if name, ok := d.fsys.decodeName(e.Name()); ok {
+				list = append(list, &dirEntry{DirEntry: e, fsys: d.fsys, name: name})
+			}
+		}
+		if n <= 0 || err != nil || len(list) > 0 {
+			if err == io.EOF && len(list) > 0 {
+				err = nil
+			}
+			return list, err
+		}
+	}
+}
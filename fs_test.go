@@ -0,0 +1,85 @@
+package clockwork
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.txt":     {Data: []byte(Base32.EncodeToString([]byte("Hello, world!")))},
+		"dir/empty.txt": {Data: []byte{}},
+		"dir/foo.txt":   {Data: []byte(Base32.EncodeToString([]byte("foobar")))},
+	}
+	cfs := NewFS(Base32, fsys)
+	if err := fstest.TestFS(cfs, "hello.txt", "dir/empty.txt", "dir/foo.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(cfs, "dir/foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "foobar" {
+		t.Errorf("got %q, want %q", data, "foobar")
+	}
+	info, err := fs.Stat(cfs, "hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 13 {
+		t.Errorf("got size %d, want 13", info.Size())
+	}
+}
+
+func TestFS_EncodedNames(t *testing.T) {
+	name := func(s string) string {
+		return EncodeFilename([]byte(s))
+	}
+	long := strings.Repeat("x", 155)
+	fsys := fstest.MapFS{
+		name("hello.txt"):                   {Data: []byte(Base32.EncodeToString([]byte("Hello, world!")))},
+		name("dir") + "/" + name("foo.txt"): {Data: []byte(Base32.EncodeToString([]byte("foobar")))},
+		name("dir") + "/" + name("A b:c*?"): {Data: []byte{}},
+		name("dir") + "/" + name(long):      {Data: []byte{}},
+
+		// these entries are skipped.
+		"not-encoded":           {Data: []byte{}},
+		"csqpyrk1":              {Data: []byte{}}, // not canonical
+		name("a/b"):             {Data: []byte{}},
+		name(".."):              {Data: []byte{}},
+		"dir/" + name("."):      {Data: []byte{}},
+		"dir/not-encoded.txt":   {Data: []byte{}},
+		"dir/" + name("dir/x"):  {Data: []byte{}},
+		"dir/_":                 {Data: []byte{}}, // empty name
+		"dir/" + name(long+"x"): {Data: []byte{}}, // split into directories
+	}
+	cfs := &FS{FS: fsys, Encoding: Base32, EncodedNames: true}
+	if err := fstest.TestFS(cfs, "hello.txt", "dir/foo.txt", "dir/A b:c*?", "dir/"+long); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := fs.ReadDir(cfs, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(entries))
+	}
+	data, err := fs.ReadFile(cfs, "dir/foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "foobar" {
+		t.Errorf("got %q, want %q", data, "foobar")
+	}
+	if _, err := fs.Stat(cfs, "not-encoded"); err == nil {
+		t.Error("want error")
+	}
+	if _, err := cfs.Open("dir/" + long + "x"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("too long name: want fs.ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package clockwork
+
+import (
+	"strconv"
+	"strings"
+)
+
+// How to handle the bytes that are not in the alphabet.
+const (
+	garbageAbort   = iota // stop decoding with CorruptInputError
+	garbageIgnore         // drop them silently
+	garbageCollect        // drop them and report CorruptInputErrors
+)
+
+// IgnoreGarbage creates a new encoding identical to enc except that
+// decoding silently drops the bytes that are not in the alphabet,
+// like the --ignore-garbage option of the base32 command.
+func (enc Encoding) IgnoreGarbage() *Encoding {
+	enc.garbage = garbageIgnore
+	return &enc
+}
+
+// CollectErrors creates a new encoding identical to enc except that
+// decoding skips the bytes that are not in the alphabet and continues.
+// The offsets of all the skipped bytes are reported as CorruptInputErrors
+// after the whole input is decoded.
+func (enc Encoding) CollectErrors() *Encoding {
+	enc.garbage = garbageCollect
+	return &enc
+}
+
+// CorruptInputErrors is a list of decoding errors
+// returned by the encodings created by CollectErrors.
+type CorruptInputErrors []CorruptInputError
+
+func (e CorruptInputErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var buf strings.Builder
+	buf.WriteString("illegal clockwork base32 data at input bytes ")
+	for i, pos := range e {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.FormatInt(int64(pos), 10))
+	}
+	return buf.String()
+}
+
+// Unwrap returns the errors in e.
+func (e CorruptInputErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether target is a CorruptInputError in e.
+// errors.Is uses Unwrap only on Go 1.20 and later, so Is covers the older versions.
+func (e CorruptInputErrors) Is(target error) bool {
+	pos, ok := target.(CorruptInputError)
+	if !ok {
+		return false
+	}
+	for _, err := range e {
+		if err == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// As sets target to the first error in e if target is a *CorruptInputError.
+// errors.As uses Unwrap only on Go 1.20 and later, so As covers the older versions.
+func (e CorruptInputErrors) As(target interface{}) bool {
+	pos, ok := target.(*CorruptInputError)
+	if !ok || len(e) == 0 {
+		return false
+	}
+	*pos = e[0]
+	return true
+}
+
+// stripGarbage appends the symbols of src to dst, and returns the extended buffer.
+// If enc collects errors, the offsets of the other bytes are appended to errs.
+// offset is the offset of src in the whole input.
+// keep is a byte that is appended as well as the symbols, or -1.
+// dst may overlap src if &dst[len(dst)] <= &src[0].
+func (enc *Encoding) stripGarbage(dst, src []byte, offset int64, errs CorruptInputErrors, keep int) ([]byte, CorruptInputErrors) {
+	for i, c := range src {
+		if enc.value(c) != 0xFF || int(c) == keep {
+			dst = append(dst, c)
+		} else if enc.garbage == garbageCollect {
+			errs = append(errs, CorruptInputError(offset+int64(i)))
+		}
+	}
+	return dst, errs
+}
+
+// decodeGarbage decodes src that may contain the bytes that are not in the alphabet.
+func (enc *Encoding) decodeGarbage(dst, src []byte) (n int, err error) {
+	symbols, errs := enc.stripGarbage(make([]byte, 0, len(src)), src, 0, nil, -1)
+	n, err = enc.decodeSymbols(dst, symbols)
+	if err == nil && len(errs) > 0 {
+		err = errs
+	}
+	return n, err
+}
@@ -0,0 +1,142 @@
+package clockwork
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var testCasesGarbage = []struct {
+	input string
+	plain string
+	errs  CorruptInputErrors
+}{
+	{"", "", nil},
+	{"CSQPYRK1E8", "foobar", nil},
+	{"CSQP YRK1\nE8\n", "foobar", CorruptInputErrors{4, 9, 12}},
+	{"-CSQPYRK1E8-", "foobar", CorruptInputErrors{0, 11}},
+	{"c-s-q-p-y-r-k-1-e-8", "foobar", CorruptInputErrors{1, 3, 5, 7, 9, 11, 13, 15, 17}},
+	{"***", "", CorruptInputErrors{0, 1, 2}},
+}
+
+func TestIgnoreGarbage(t *testing.T) {
+	enc := NewEncoding().IgnoreGarbage()
+	for _, testCase := range testCasesGarbage {
+		got, err := enc.DecodeString(testCase.input)
+		if err != nil {
+			t.Errorf("error while decoding %q: %v", testCase.input, err)
+		}
+		if string(got) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.input, testCase.plain, got)
+		}
+	}
+}
+
+func TestIgnoreGarbage_Decoder(t *testing.T) {
+	enc := NewEncoding().IgnoreGarbage()
+	for _, testCase := range testCasesGarbage {
+		r := iotest.OneByteReader(strings.NewReader(testCase.input))
+		got, err := io.ReadAll(NewDecoder(enc, r))
+		if err != nil {
+			t.Errorf("error while decoding %q: %v", testCase.input, err)
+		}
+		if string(got) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.input, testCase.plain, got)
+		}
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	enc := NewEncoding().CollectErrors()
+	for _, testCase := range testCasesGarbage {
+		got, err := enc.DecodeString(testCase.input)
+		if testCase.errs == nil {
+			if err != nil {
+				t.Errorf("error while decoding %q: %v", testCase.input, err)
+			}
+		} else if !reflect.DeepEqual(err, testCase.errs) {
+			t.Errorf("decoding %q: want %v, got %v", testCase.input, testCase.errs, err)
+		}
+		if string(got) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.input, testCase.plain, got)
+		}
+	}
+}
+
+func TestCollectErrors_Decoder(t *testing.T) {
+	enc := NewEncoding().CollectErrors()
+	for _, testCase := range testCasesGarbage {
+		r := iotest.HalfReader(strings.NewReader(testCase.input))
+		got, err := io.ReadAll(NewDecoder(enc, r))
+		if testCase.errs == nil {
+			if err != nil {
+				t.Errorf("error while decoding %q: %v", testCase.input, err)
+			}
+		} else if !reflect.DeepEqual(err, testCase.errs) {
+			t.Errorf("decoding %q: want %v, got %v", testCase.input, testCase.errs, err)
+		}
+		if string(got) != testCase.plain {
+			t.Errorf("decoded %q, expected %q, actual %q\n",
+				testCase.input, testCase.plain, got)
+		}
+	}
+}
+
+func TestCollectErrors_Big(t *testing.T) {
+	enc := NewEncoding().CollectErrors()
+	input := strings.Repeat(bigtest.encoded+"\n", 100)
+	got, err := io.ReadAll(NewDecoder(enc, strings.NewReader(input)))
+	var errs CorruptInputErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want CorruptInputErrors, got %v", err)
+	}
+	if len(errs) != 100 {
+		t.Errorf("want 100 errors, got %d", len(errs))
+	}
+	for i, pos := range errs {
+		want := CorruptInputError((len(bigtest.encoded)+1)*(i+1) - 1)
+		if pos != want {
+			t.Errorf("errs[%d]: want %d, got %d", i, want, pos)
+		}
+	}
+
+	want, err := Base32.DecodeString(strings.Repeat(bigtest.encoded, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("unexpected decoded data: %q", got)
+	}
+}
+
+func TestCorruptInputErrors(t *testing.T) {
+	err := CorruptInputErrors{1, 5, 9}
+	if err.Error() != "illegal clockwork base32 data at input bytes 1, 5, 9" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+	if err := (CorruptInputErrors{3}); err.Error() != CorruptInputError(3).Error() {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+	if errs := err.Unwrap(); len(errs) != 3 || errs[1] != CorruptInputError(5) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	// Is and As work without the support of Unwrap() []error.
+	if !err.Is(CorruptInputError(5)) || err.Is(CorruptInputError(4)) {
+		t.Error("unexpected result of Is")
+	}
+	var pos CorruptInputError
+	if !err.As(&pos) || pos != 1 {
+		t.Errorf("unexpected result of As: %d", pos)
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", err), CorruptInputError(9)) {
+		t.Error("errors.Is: want true")
+	}
+}
@@ -0,0 +1,9 @@
+// Package httpx provides net/http helpers for Clockwork Base32 encoded
+// path and query parameters.
+//
+// The helpers rely on the routing patterns introduced in Go 1.22
+// and Request.Pattern introduced in Go 1.23,
+// so the package is empty on older versions of Go.
+// The main module must enable the patterns, i.e. declare go 1.22 or later,
+// or set GODEBUG=httpmuxgo121=0.
+package httpx
@@ -0,0 +1,183 @@
+//go:build go1.23
+// +build go1.23
+
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	clockwork "github.com/shogo82148/go-clockwork-base32"
+)
+
+// ErrMissing is returned when a parameter is missing or empty.
+var ErrMissing = errors.New("httpx: missing parameter")
+
+// ParamError records a failure to decode a request parameter.
+type ParamError struct {
+	// Source is "path" or "query".
+	Source string
+
+	// Name is the name of the parameter.
+	Name string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	return "httpx: invalid " + e.Source + " parameter " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// PathValue decodes the value for the named path wildcard in the request
+// using clockwork.Base32.
+func PathValue(r *http.Request, name string) ([]byte, error) {
+	return decode("path", name, r.PathValue(name))
+}
+
+// QueryValue decodes the first value of the named query parameter in the request
+// using clockwork.Base32.
+func QueryValue(r *http.Request, name string) ([]byte, error) {
+	return decode("query", name, r.URL.Query().Get(name))
+}
+
+func decode(source, name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, &ParamError{Source: source, Name: name, Err: ErrMissing}
+	}
+	data, err := clockwork.Base32.DecodeString(value)
+	if err != nil {
+		return nil, &ParamError{Source: source, Name: name, Err: err}
+	}
+	return data, nil
+}
+
+// Error replies to the request with a 400 Bad Request describing err.
+// If err contains a clockwork.CorruptInputError,
+// the message includes the position of the invalid symbol.
+func Error(w http.ResponseWriter, err error) {
+	var msg string
+	var perr *ParamError
+	var cerr clockwork.CorruptInputError
+	switch {
+	case errors.As(err, &perr) && errors.As(err, &cerr):
+		msg = fmt.Sprintf("invalid %s parameter %q: illegal symbol at position %d", perr.Source, perr.Name, int64(cerr))
+	case errors.As(err, &perr) && errors.Is(err, ErrMissing):
+		msg = fmt.Sprintf("missing %s parameter %q", perr.Source, perr.Name)
+	case errors.As(err, &perr):
+		msg = fmt.Sprintf("invalid %s parameter %q", perr.Source, perr.Name)
+	case errors.As(err, &cerr):
+		msg = fmt.Sprintf("illegal symbol at position %d", int64(cerr))
+	default:
+		msg = "bad request"
+	}
+	http.Error(w, msg, http.StatusBadRequest)
+}
+
+// Canonicalize returns a handler that redirects requests whose named path wildcards
+// are not in the canonical form, such as lower case letters or the aliases o and l,
+// to the canonical URL. GET and HEAD requests are redirected with 301 Moved Permanently,
+// and other methods with 308 Permanent Redirect to preserve the body.
+// The symbols are mapped one by one, so the redirect never changes the decoded value.
+// Values that are not valid encodings are passed to next unchanged.
+//
+// The handler must be registered to an http.ServeMux with a pattern that defines the wildcards.
+// Requests that are not routed by a ServeMux have no pattern,
+// so they are passed to next without redirects.
+// Only the path segments of the wildcards in the pattern are rewritten,
+// and wildcards that match multiple segments, such as {path...}, are ignored.
+//
+// The redirect keeps the prefix of the requested path that was removed
+// before the request reached the ServeMux, such as by http.StripPrefix.
+// If the path of the request is not a suffix of the requested path,
+// the request is passed to next without redirects.
+func Canonicalize(next http.Handler, names ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		positions := wildcardPositions(r.Pattern)
+		segments := strings.Split(r.URL.EscapedPath(), "/")
+		var redirect bool
+		for _, name := range names {
+			i, ok := positions[name]
+			if !ok || i >= len(segments) {
+				continue
+			}
+			value := r.PathValue(name)
+			if segment, err := url.PathUnescape(segments[i]); err != nil || segment != value {
+				continue
+			}
+			if canonical, ok := clockwork.Base32.CanonicalString(value); ok && canonical != value {
+				segments[i] = canonical
+				redirect = true
+			}
+		}
+		prefix, ok := strippedPrefix(r)
+		if !redirect || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target := prefix + strings.Join(segments, "/")
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, target, code)
+	})
+}
+
+// strippedPrefix returns the prefix of the requested path that was removed
+// from the path of r, such as by http.StripPrefix.
+// It reports false if the path of r is not a suffix of the requested path.
+func strippedPrefix(r *http.Request) (string, bool) {
+	if r.RequestURI == "" {
+		// r is not a server request.
+		return "", true
+	}
+	u, err := url.ParseRequestURI(r.RequestURI)
+	if err != nil {
+		return "", false
+	}
+	requested, path := u.EscapedPath(), r.URL.EscapedPath()
+	if !strings.HasSuffix(requested, path) {
+		return "", false
+	}
+	return requested[:len(requested)-len(path)], true
+}
+
+// wildcardPositions returns the indexes of the path segments of
+// the single segment wildcards in the ServeMux pattern.
+// The indexes count the empty segment before the leading slash.
+func wildcardPositions(pattern string) map[string]int {
+	// strip the method and the host.
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i+1:], " \t")
+	}
+	i := strings.IndexByte(pattern, '/')
+	if i < 0 {
+		return nil
+	}
+
+	positions := make(map[string]int)
+	for i, segment := range strings.Split(pattern[i:], "/") {
+		if len(segment) < 2 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+			continue
+		}
+		name := segment[1 : len(segment)-1]
+		if name == "$" || strings.HasSuffix(name, "...") {
+			continue
+		}
+		positions[name] = i
+	}
+	return positions
+}
@@ -0,0 +1,188 @@
+//go:build go1.23
+// +build go1.23
+
+// The module declares go 1.16, which selects the legacy ServeMux patterns by default.
+//go:debug httpmuxgo121=0
+
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	clockwork "github.com/shogo82148/go-clockwork-base32"
+)
+
+func TestPathValue(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/CSQPY", nil)
+	req.SetPathValue("id", "CSQPY")
+	got, err := PathValue(req, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foo" {
+		t.Errorf("got %q, want %q", got, "foo")
+	}
+
+	req.SetPathValue("id", "CSQ*Y")
+	_, err = PathValue(req, "id")
+	var cerr clockwork.CorruptInputError
+	if !errors.As(err, &cerr) || cerr != 3 {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err = PathValue(req, "missing")
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQueryValue(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?id=csqpy", nil)
+	got, err := QueryValue(req, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foo" {
+		t.Errorf("got %q, want %q", got, "foo")
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  &ParamError{Source: "path", Name: "id", Err: clockwork.CorruptInputError(3)},
+			want: "invalid path parameter \"id\": illegal symbol at position 3\n",
+		},
+		{
+			err:  &ParamError{Source: "query", Name: "id", Err: ErrMissing},
+			want: "missing query parameter \"id\"\n",
+		},
+		{
+			err:  clockwork.CorruptInputError(5),
+			want: "illegal symbol at position 5\n",
+		},
+		{
+			err:  errors.New("something"),
+			want: "bad request\n",
+		},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		Error(rec, tt.err)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/v1/files/{id}", Canonicalize(next, "id"))
+	mux.Handle("POST /orgs/{org}/users/{id}", Canonicalize(next, "org", "id"))
+	mux.Handle("/static/{path...}", Canonicalize(next, "path"))
+
+	tests := []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		// canonical values
+		{http.MethodGet, "/v1/files/CSQPY", http.StatusOK, ""},
+		{http.MethodGet, "/v1/files/V0", http.StatusOK, ""},
+
+		// only the segment of the wildcard is rewritten.
+		{http.MethodGet, "/v1/files/csqpy?x=1", http.StatusMovedPermanently, "/v1/files/CSQPY?x=1"},
+		{http.MethodHead, "/v1/files/csqpyrkle8", http.StatusMovedPermanently, "/v1/files/CSQPYRK1E8"},
+		{http.MethodGet, "/v1/files/v0", http.StatusMovedPermanently, "/v1/files/V0"},
+		{http.MethodPost, "/orgs/c5q7j/users/1o", http.StatusPermanentRedirect, "/orgs/C5Q7J/users/10"},
+		{http.MethodPost, "/orgs/l0/users/l0", http.StatusPermanentRedirect, "/orgs/10/users/10"},
+
+		// invalid values are passed to the handler.
+		{http.MethodGet, "/v1/files/v1", http.StatusOK, ""},  // non-zero padding bits
+		{http.MethodGet, "/v1/files/0", http.StatusOK, ""},   // invalid length
+		{http.MethodGet, "/v1/files/CSQ", http.StatusOK, ""}, // invalid length
+		{http.MethodGet, "/v1/files/CSQ*Y", http.StatusOK, ""},
+
+		// multi-segment wildcards are ignored.
+		{http.MethodGet, "/static/csqpy", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(""))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s %s: got status %d, want %d", tt.method, tt.target, rec.Code, tt.code)
+		}
+		if got := rec.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s %s: got location %q, want %q", tt.method, tt.target, got, tt.location)
+		}
+	}
+}
+
+func TestCanonicalize_StripPrefix(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	})
+	api := http.NewServeMux()
+	api.Handle("/files/{id}", Canonicalize(next, "id"))
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", api))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/csqpy?x=1", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMovedPermanently {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusMovedPermanently)
+	}
+	if got, want := rec.Header().Get("Location"), "/api/files/CSQPY?x=1"; got != want {
+		t.Errorf("got location %q, want %q", got, want)
+	}
+}
+
+func TestCanonicalize_NoPattern(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(http.MethodGet, "/files/csqpy", nil)
+	rec := httptest.NewRecorder()
+	Canonicalize(next, "id").ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestWildcardPositions(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    map[string]int
+	}{
+		{"/v1/files/{id}", map[string]int{"id": 3}},
+		{"GET example.com/orgs/{org}/users/{id}/{$}", map[string]int{"org": 2, "id": 4}},
+		{"/static/{path...}", map[string]int{}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := wildcardPositions(tt.pattern)
+		if len(got) != len(tt.want) {
+			t.Errorf("wildcardPositions(%q) = %v, want %v", tt.pattern, got, tt.want)
+			continue
+		}
+		for name, i := range tt.want {
+			if got[name] != i {
+				t.Errorf("wildcardPositions(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		}
+	}
+}
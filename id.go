@@ -0,0 +1,243 @@
+package clockwork
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// IDSeparator separates the prefix and the body of identifiers.
+const IDSeparator = '_'
+
+var (
+	idTypesMu sync.RWMutex
+	idTypes   = map[string]*IDType{}
+)
+
+// An IDType is a type of identifiers, such as users or organizations.
+// It is identified by the prefix of identifiers.
+type IDType struct {
+	prefix string
+}
+
+// RegisterIDType registers a new identifier type with the prefix.
+// The prefix must start with a lower case ASCII letter
+// and consist of lower case ASCII letters and digits.
+// It panics if the prefix is invalid or already registered.
+func RegisterIDType(prefix string) *IDType {
+	if !validIDPrefix(prefix) {
+		panic("clockwork: invalid id prefix " + prefix)
+	}
+
+	idTypesMu.Lock()
+	defer idTypesMu.Unlock()
+	if _, dup := idTypes[prefix]; dup {
+		panic("clockwork: RegisterIDType called twice for prefix " + prefix)
+	}
+	t := &IDType{prefix: prefix}
+	idTypes[prefix] = t
+	return t
+}
+
+func validIDPrefix(prefix string) bool {
+	if prefix == "" || prefix[0] < 'a' || prefix[0] > 'z' {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupIDType(prefix string) *IDType {
+	idTypesMu.RLock()
+	defer idTypesMu.RUnlock()
+	return idTypes[prefix]
+}
+
+// Prefix returns the prefix of the identifier type.
+func (t *IDType) Prefix() string {
+	return t.prefix
+}
+
+// New returns a new identifier of the type t with the body.
+func (t *IDType) New(body []byte) ID {
+	return ID{Type: t, Body: body}
+}
+
+// Parse parses an identifier of the type t.
+// It returns an *IDPrefixError if s has another prefix.
+func (t *IDType) Parse(s string) (ID, error) {
+	id := ID{Type: t}
+	if err := id.UnmarshalText([]byte(s)); err != nil {
+		return ID{}, err
+	}
+	return id, nil
+}
+
+// ID is a typed identifier, such as "usr_0ABC".
+// The body of the identifier is encoded by Base32.
+type ID struct {
+	// Type is the type of the identifier.
+	// If Type is set before unmarshaling, the identifier must have its prefix;
+	// otherwise, any registered prefix is accepted.
+	Type *IDType
+
+	// Body is the binary representation of the identifier.
+	Body []byte
+}
+
+// ParseID parses an identifier of any registered type.
+func ParseID(s string) (ID, error) {
+	var id ID
+	if err := id.UnmarshalText([]byte(s)); err != nil {
+		return ID{}, err
+	}
+	return id, nil
+}
+
+// IsZero reports whether id has no body.
+func (id ID) IsZero() bool {
+	return len(id.Body) == 0
+}
+
+// String returns the text representation of id.
+func (id ID) String() string {
+	text, err := id.MarshalText()
+	if err != nil {
+		return "<invalid id>"
+	}
+	return string(text)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The zero ID is marshaled to an empty text.
+func (id ID) MarshalText() ([]byte, error) {
+	if id.IsZero() {
+		return []byte{}, nil
+	}
+	if id.Type == nil {
+		return nil, errors.New("clockwork: id has no type")
+	}
+	return id.AppendText(make([]byte, 0, len(id.Type.prefix)+1+Base32.EncodedLen(len(id.Body))))
+}
+
+// AppendText implements encoding.TextAppender.
+// It appends the text representation of id to b.
+func (id ID) AppendText(b []byte) ([]byte, error) {
+	if id.IsZero() {
+		return b, nil
+	}
+	if id.Type == nil {
+		return nil, errors.New("clockwork: id has no type")
+	}
+	b = append(b, id.Type.prefix...)
+	b = append(b, IDSeparator)
+	return Base32.AppendEncode(b, id.Body), nil
+}
+
+// AppendBinary implements encoding.BinaryAppender.
+// The binary representation of id is the same as the text representation,
+// because the prefix is needed to restore the type.
+func (id ID) AppendBinary(b []byte) ([]byte, error) {
+	return id.AppendText(b)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		id.Body = nil
+		return nil
+	}
+
+	s := string(text)
+	i := strings.IndexByte(s, IDSeparator)
+	if i < 0 {
+		return fmt.Errorf("clockwork: invalid id %q: missing prefix", s)
+	}
+	prefix, body := s[:i], s[i+1:]
+
+	t := id.Type
+	if t == nil {
+		t = lookupIDType(prefix)
+		if t == nil {
+			return &IDPrefixError{Got: prefix}
+		}
+	} else if t.prefix != prefix {
+		return &IDPrefixError{Want: t.prefix, Got: prefix}
+	}
+	if body == "" {
+		return fmt.Errorf("clockwork: invalid id %q: empty body", s)
+	}
+
+	buf, err := Base32.DecodeString(body)
+	if err != nil {
+		if err, ok := err.(CorruptInputError); ok {
+			return err + CorruptInputError(i+1)
+		}
+		return err
+	}
+	if len(buf) == 0 {
+		return fmt.Errorf("clockwork: invalid id %q: empty body", s)
+	}
+
+	// reject the bodies that have another spelling than the aliases,
+	// e.g. non-zero padding bits, so that an ID has only one text representation.
+	if _, ok := Base32.CanonicalString(body); !ok {
+		return fmt.Errorf("clockwork: invalid id %q: non-canonical body", s)
+	}
+	id.Type = t
+	id.Body = buf
+	return nil
+}
+
+// Scan implements sql.Scanner.
+// NULL is scanned as the zero ID.
+func (id *ID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		id.Body = nil
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(src))
+	case []byte:
+		return id.UnmarshalText(src)
+	}
+	return fmt.Errorf("clockwork: cannot scan %T into ID", src)
+}
+
+// Value implements driver.Valuer.
+// The zero ID is stored as NULL.
+func (id ID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	text, err := id.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// An IDPrefixError is returned when an identifier has an unexpected prefix.
+type IDPrefixError struct {
+	// Want is the expected prefix.
+	// It is empty if any registered prefix is accepted.
+	Want string
+
+	// Got is the prefix of the identifier.
+	Got string
+}
+
+func (e *IDPrefixError) Error() string {
+	if e.Want == "" {
+		return "clockwork: unknown id prefix " + strconv.Quote(e.Got)
+	}
+	return "clockwork: id prefix " + strconv.Quote(e.Got) + " does not match " + strconv.Quote(e.Want)
+}
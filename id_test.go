@@ -0,0 +1,238 @@
+package clockwork
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var (
+	testUserType = RegisterIDType("usr")
+	testOrgType  = RegisterIDType("org")
+
+	_ encoding.TextMarshaler   = ID{}
+	_ encoding.TextUnmarshaler = (*ID)(nil)
+	_ sql.Scanner              = (*ID)(nil)
+	_ driver.Valuer            = ID{}
+)
+
+func TestRegisterIDType_Panic(t *testing.T) {
+	tests := []string{"", "Usr", "1usr", "us_r", "usr"}
+	for _, prefix := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterIDType(%q) did not panic", prefix)
+				}
+			}()
+			RegisterIDType(prefix)
+		}()
+	}
+}
+
+func TestID_MarshalText(t *testing.T) {
+	id := testUserType.New([]byte("foobar"))
+	text, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "usr_CSQPYRK1E8" {
+		t.Errorf("want %q, got %q", "usr_CSQPYRK1E8", text)
+	}
+	if id.String() != "usr_CSQPYRK1E8" {
+		t.Errorf("want %q, got %q", "usr_CSQPYRK1E8", id.String())
+	}
+
+	text, err = ID{}.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "" {
+		t.Errorf("want empty, got %q", text)
+	}
+
+	if _, err := (ID{Body: []byte("foobar")}).MarshalText(); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID("org_csqpyrk1e8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Type != testOrgType {
+		t.Errorf("unexpected type: %q", id.Type.Prefix())
+	}
+	if string(id.Body) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", id.Body)
+	}
+}
+
+func TestParseID_Error(t *testing.T) {
+	_, err := ParseID("foo_CSQPYRK1E8")
+	var perr *IDPrefixError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want IDPrefixError, got %v", err)
+	}
+	if perr.Want != "" || perr.Got != "foo" {
+		t.Errorf("unexpected error: %#v", perr)
+	}
+
+	if _, err := ParseID("CSQPYRK1E8"); err == nil {
+		t.Error("missing prefix: want error, got nil")
+	}
+	if _, err := ParseID("usr_"); err == nil {
+		t.Error("empty body: want error, got nil")
+	}
+	if _, err := ParseID("usr_0"); err == nil {
+		t.Error("empty decoded body: want error, got nil")
+	}
+	if _, err := ParseID("usr_CSQPZ"); err == nil {
+		t.Error("non-zero padding bits: want error, got nil")
+	}
+	if _, err := ParseID("usr_CSQPY"); err != nil {
+		t.Errorf("canonical body: unexpected error: %v", err)
+	}
+	if _, err := ParseID("usr_csqpy"); err != nil {
+		t.Errorf("lower case body: unexpected error: %v", err)
+	}
+	if _, err := ParseID("usr_CSQPYRKLE8"); err != nil {
+		t.Errorf("aliased body: unexpected error: %v", err)
+	}
+	if _, err := ParseID("usr_CSQ*"); err != CorruptInputError(7) {
+		t.Errorf("invalid body: want CorruptInputError(7), got %v", err)
+	}
+}
+
+func TestIDType_Parse(t *testing.T) {
+	if _, err := testUserType.Parse("usr_CSQPYRK1E8"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := testUserType.Parse("org_CSQPYRK1E8")
+	var perr *IDPrefixError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want IDPrefixError, got %v", err)
+	}
+	if perr.Want != "usr" || perr.Got != "org" {
+		t.Errorf("unexpected error: %#v", perr)
+	}
+	if perr.Error() != `clockwork: id prefix "org" does not match "usr"` {
+		t.Errorf("unexpected message: %s", perr.Error())
+	}
+}
+
+func TestID_JSON(t *testing.T) {
+	type user struct {
+		ID ID `json:"id"`
+	}
+
+	data, err := json.Marshal(user{ID: testUserType.New([]byte("foobar"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"id":"usr_CSQPYRK1E8"}` {
+		t.Errorf("unexpected json: %s", data)
+	}
+
+	u := user{ID: ID{Type: testUserType}}
+	if err := json.Unmarshal(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	if string(u.ID.Body) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", u.ID.Body)
+	}
+
+	u = user{ID: ID{Type: testUserType}}
+	if err := json.Unmarshal([]byte(`{"id":"org_CSQPYRK1E8"}`), &u); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestID_Scan(t *testing.T) {
+	tests := []interface{}{
+		"usr_CSQPYRK1E8",
+		[]byte("usr_CSQPYRK1E8"),
+	}
+	for _, src := range tests {
+		id := ID{Type: testUserType}
+		if err := id.Scan(src); err != nil {
+			t.Errorf("Scan(%#v): %v", src, err)
+			continue
+		}
+		if string(id.Body) != "foobar" {
+			t.Errorf("Scan(%#v): want %q, got %q", src, "foobar", id.Body)
+		}
+	}
+
+	id := testUserType.New([]byte("foobar"))
+	if err := id.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !id.IsZero() {
+		t.Errorf("want zero, got %v", id)
+	}
+
+	if err := id.Scan(42); err == nil {
+		t.Error("want error, got nil")
+	}
+	if err := id.Scan("org_CSQPYRK1E8"); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestID_Value(t *testing.T) {
+	v, err := testUserType.New([]byte("foobar")).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "usr_CSQPYRK1E8" {
+		t.Errorf("want %q, got %#v", "usr_CSQPYRK1E8", v)
+	}
+
+	v, err = ID{}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != nil {
+		t.Errorf("want nil, got %#v", v)
+	}
+}
+
+func TestID_AppendText(t *testing.T) {
+	id := testUserType.New([]byte("foobar"))
+	got, err := id.AppendText([]byte("id="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "id=usr_CSQPYRK1E8" {
+		t.Errorf("got %q, want %q", got, "id=usr_CSQPYRK1E8")
+	}
+
+	got, err = ID{}.AppendText([]byte("id="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "id=" {
+		t.Errorf("got %q, want %q", got, "id=")
+	}
+
+	if _, err := (ID{Body: []byte("foo")}).AppendText(nil); err == nil {
+		t.Error("want error for id without type")
+	}
+}
+
+func TestID_AppendBinary(t *testing.T) {
+	id := testUserType.New([]byte("foobar"))
+	got, err := id.AppendBinary([]byte("id="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "id=usr_CSQPYRK1E8" {
+		t.Errorf("got %q, want %q", got, "id=usr_CSQPYRK1E8")
+	}
+}
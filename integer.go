@@ -0,0 +1,40 @@
+package clockwork
+
+import "fmt"
+
+// Uint64Len is the length of the base32 encoding of a uint64 by EncodeUint64.
+const Uint64Len = 13
+
+// AppendUint64 appends the base32 encoding of v to dst.
+// The encoding always has Uint64Len symbols, and preserves the order of integers.
+func (enc *Encoding) AppendUint64(dst []byte, v uint64) []byte {
+	// the first symbol has the top 4 bits, and the rest have 5 bits each.
+	dst = append(dst, enc.symbol(byte(v>>60)))
+	for shift := 55; shift >= 0; shift -= 5 {
+		dst = append(dst, enc.symbol(byte(v>>uint(shift))&0x1F))
+	}
+	return dst
+}
+
+// EncodeUint64 returns the base32 encoding of v in Uint64Len symbols.
+func (enc *Encoding) EncodeUint64(v uint64) string {
+	var buf [Uint64Len]byte
+	return string(enc.AppendUint64(buf[:0], v))
+}
+
+// DecodeUint64 returns the integer represented by the base32 string s
+// encoded by EncodeUint64.
+func (enc *Encoding) DecodeUint64(s string) (uint64, error) {
+	if len(s) != Uint64Len {
+		return 0, fmt.Errorf("clockwork: want %d symbols, got %d", Uint64Len, len(s))
+	}
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		b := enc.value(s[i])
+		if b == 0xFF || i == 0 && b >= 0x10 {
+			return 0, CorruptInputError(i)
+		}
+		v = v<<5 | uint64(b)
+	}
+	return v, nil
+}
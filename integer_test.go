@@ -0,0 +1,76 @@
+package clockwork
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncodeUint64(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "0000000000000"},
+		{1, "0000000000001"},
+		{31, "000000000000Z"},
+		{32, "0000000000010"},
+		{math.MaxUint64, "FZZZZZZZZZZZZ"},
+	}
+	for _, enc := range []*Encoding{Base32, Base32.ConstantTime()} {
+		for _, tt := range tests {
+			got := enc.EncodeUint64(tt.v)
+			if got != tt.want {
+				t.Errorf("EncodeUint64(%d) = %q, want %q", tt.v, got, tt.want)
+			}
+			v, err := enc.DecodeUint64(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != tt.v {
+				t.Errorf("DecodeUint64(%q) = %d, want %d", got, v, tt.v)
+			}
+		}
+	}
+}
+
+func TestEncodeUint64_Order(t *testing.T) {
+	prev := Base32.EncodeUint64(0)
+	for v := uint64(1); v < 1<<20; v = v*3 + 1 {
+		s := Base32.EncodeUint64(v)
+		if strings.Compare(prev, s) >= 0 {
+			t.Errorf("%q >= %q", prev, s)
+		}
+		prev = s
+	}
+}
+
+func TestDecodeUint64_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		pos int
+	}{
+		{"", -1},
+		{"000000000000", -1},
+		{"00000000000000", -1},
+		{"G000000000000", 0},
+		{"00000*0000000", 5},
+		{"000000000000U", 12},
+	}
+	for _, tt := range tests {
+		_, err := Base32.DecodeUint64(tt.s)
+		if err == nil {
+			t.Errorf("DecodeUint64(%q): want error", tt.s)
+			continue
+		}
+		if pos, ok := err.(CorruptInputError); ok != (tt.pos >= 0) || ok && int(pos) != tt.pos {
+			t.Errorf("DecodeUint64(%q): unexpected error: %v", tt.s, err)
+		}
+	}
+
+	// aliases are accepted.
+	v, err := Base32.DecodeUint64("000000000000o")
+	if err != nil || v != 0 {
+		t.Errorf("DecodeUint64 with alias = %d, %v", v, err)
+	}
+}
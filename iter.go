@@ -0,0 +1,111 @@
+//go:build go1.23
+// +build go1.23
+
+package clockwork
+
+import "iter"
+
+// EncodeChunks returns an iterator over the base32 encoding of the chunks of src.
+// Partial blocks are carried over to the next chunk, so the concatenation of the
+// encoded chunks is the same as the output of NewEncoder.
+// The yielded slices are only valid until the next iteration.
+func (enc *Encoding) EncodeChunks(src iter.Seq[[]byte]) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		var buf [5]byte
+		var nbuf int
+		var out []byte
+		for p := range src {
+			out = out[:0]
+
+			// Leading fringe.
+			if nbuf > 0 {
+				n := copy(buf[nbuf:], p)
+				nbuf += n
+				p = p[n:]
+				if nbuf < len(buf) {
+					continue
+				}
+				out = enc.AppendEncode(out, buf[:])
+				nbuf = 0
+			}
+
+			// Large interior chunks.
+			n := len(p) / 5 * 5
+			out = enc.AppendEncode(out, p[:n])
+
+			// Trailing fringe.
+			nbuf = copy(buf[:], p[n:])
+			if len(out) > 0 && !yield(out) {
+				return
+			}
+		}
+		if nbuf > 0 {
+			yield(enc.AppendEncode(out[:0], buf[:nbuf]))
+		}
+	}
+}
+
+// DecodeChunks returns an iterator over the data decoded from the base32 encoded chunks of src.
+// Partial blocks are carried over to the next chunk.
+// The offsets of CorruptInputError are relative to the beginning of the whole input.
+// The iteration stops after the first error.
+// The yielded slices are only valid until the next iteration.
+func (enc *Encoding) DecodeChunks(src iter.Seq[[]byte]) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		var pending, out []byte
+		var errs CorruptInputErrors
+		var nread int64
+
+		// decode decodes the first n symbols of pending.
+		decode := func(n int) ([]byte, error) {
+			if size := enc.DecodedLen(n) + 5; cap(out) < size {
+				out = make([]byte, size)
+			}
+			m, err := enc.decodeSymbols(out[:cap(out)], pending[:n])
+			if err != nil {
+				if pos, ok := err.(CorruptInputError); ok {
+					err = pos + CorruptInputError(nread-int64(len(pending)))
+				}
+				return nil, err
+			}
+			pending = pending[:copy(pending, pending[n:])]
+			return out[:m], nil
+		}
+
+		for p := range src {
+			if enc.garbage != garbageAbort {
+				pending, errs = enc.stripGarbage(pending, p, nread, errs, -1)
+			} else {
+				pending = append(pending, p...)
+			}
+			nread += int64(len(p))
+
+			n := len(pending) / 8 * 8
+			if n == 0 {
+				continue
+			}
+			data, err := decode(n)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(data, nil) {
+				return
+			}
+		}
+
+		if len(pending) > 0 {
+			data, err := decode(len(pending))
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(data, nil) {
+				return
+			}
+		}
+		if len(errs) > 0 {
+			yield(nil, errs)
+		}
+	}
+}
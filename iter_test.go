@@ -0,0 +1,125 @@
+//go:build go1.23
+// +build go1.23
+
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// chunks splits data into chunks of the sizes, repeating the sizes.
+func chunks(data []byte, sizes ...int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for i := 0; len(data) > 0; i++ {
+			n := min(sizes[i%len(sizes)], len(data))
+			if !yield(data[:n]) {
+				return
+			}
+			data = data[n:]
+		}
+	}
+}
+
+func TestEncodeChunks(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 17)
+	}
+	for _, sizes := range [][]int{{1}, {2, 3}, {4}, {5}, {7, 0, 1}, {13}, {1000}} {
+		for _, n := range []int{0, 1, 4, 5, 6, 999, 1000} {
+			var want strings.Builder
+			w := NewEncoder(Base32, &want)
+			w.Write(data[:n])
+			w.Close()
+
+			var got []byte
+			for chunk := range Base32.EncodeChunks(chunks(data[:n], sizes...)) {
+				got = append(got, chunk...)
+			}
+			if string(got) != want.String() {
+				t.Errorf("sizes %v, length %d: got %q, want %q", sizes, n, got, want.String())
+			}
+		}
+	}
+}
+
+func TestDecodeChunks(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 17)
+	}
+	for _, sizes := range [][]int{{1}, {2, 3}, {7}, {8}, {9, 0, 1}, {13}, {2000}} {
+		for _, n := range []int{0, 1, 4, 5, 6, 999, 1000} {
+			encoded := []byte(Base32.EncodeToString(data[:n]))
+			var got []byte
+			for chunk, err := range Base32.DecodeChunks(chunks(encoded, sizes...)) {
+				if err != nil {
+					t.Fatalf("sizes %v, length %d: %v", sizes, n, err)
+				}
+				got = append(got, chunk...)
+			}
+			if !bytes.Equal(got, data[:n]) {
+				t.Errorf("sizes %v, length %d: data mismatch", sizes, n)
+			}
+		}
+	}
+}
+
+func TestDecodeChunks_Error(t *testing.T) {
+	encoded := []byte(Base32.EncodeToString([]byte("foobarfoob")) + "CSQ*YRK1E8")
+	var got []byte
+	var err error
+	for chunk, e := range Base32.DecodeChunks(chunks(encoded, 3)) {
+		if e != nil {
+			err = e
+			break
+		}
+		got = append(got, chunk...)
+	}
+	if err != CorruptInputError(19) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if string(got) != "foobarfoob" {
+		t.Errorf("got %q, want %q", got, "foobarfoob")
+	}
+
+	// garbage
+	encoded = []byte("CSQPY\nRK1E8\n")
+	got = nil
+	for chunk, err := range Base32.IgnoreGarbage().DecodeChunks(chunks(encoded, 4)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, chunk...)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("got %q, want %q", got, "foobar")
+	}
+
+	err = nil
+	for _, e := range Base32.CollectErrors().DecodeChunks(chunks(encoded, 4)) {
+		if e != nil {
+			err = e
+		}
+	}
+	var errs CorruptInputErrors
+	if !errors.As(err, &errs) || !slices.Equal(errs, CorruptInputErrors{5, 11}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChunks_Break(t *testing.T) {
+	data := bytes.Repeat([]byte("foobar"), 100)
+	for range Base32.EncodeChunks(chunks(data, 10)) {
+		break
+	}
+	encoded := []byte(Base32.EncodeToString(data))
+	for range Base32.DecodeChunks(chunks(encoded, 10)) {
+		break
+	}
+}
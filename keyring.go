@@ -0,0 +1,180 @@
+package clockwork
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownKey is returned by KeyRing.Open when the token is sealed by a key that is not in the key ring.
+	ErrUnknownKey = errors.New("clockwork: unknown key")
+
+	// ErrTokenExpired is returned by KeyRing.Open when the token has expired.
+	ErrTokenExpired = errors.New("clockwork: token expired")
+)
+
+const (
+	sealedVersion    = 1
+	sealedHeaderSize = 1 + 4 // version, key id
+	sealedExpirySize = 8
+)
+
+// KeyRing seals and opens payloads with AES-GCM into Base32 tokens.
+// A token has the format version, key ID and nonce, followed by the ciphertext
+// of the expiry time and the payload. The header is authenticated as additional data.
+//
+// Keys are identified by IDs, so they can be rotated: add a new key, make it primary,
+// and remove the old key after all tokens sealed by it have expired.
+// A KeyRing is safe for concurrent use.
+type KeyRing struct {
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	mu         sync.RWMutex
+	keys       map[uint32]cipher.AEAD
+	primary    uint32
+	hasPrimary bool
+}
+
+// NewKeyRing returns a new empty key ring.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{}
+}
+
+// Add adds the AES key with the id to the key ring.
+// The key must be 16, 24 or 32 bytes long.
+// The first key added becomes the primary key.
+func (k *KeyRing) Add(id uint32, key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("clockwork: duplicated key id %d", id)
+	}
+	if k.keys == nil {
+		k.keys = make(map[uint32]cipher.AEAD)
+	}
+	k.keys[id] = aead
+	if !k.hasPrimary {
+		k.primary = id
+		k.hasPrimary = true
+	}
+	return nil
+}
+
+// SetPrimary makes the key with the id the primary key, which seals new tokens.
+func (k *KeyRing) SetPrimary(id uint32) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+	k.primary = id
+	k.hasPrimary = true
+	return nil
+}
+
+// Remove removes the key with the id from the key ring.
+// The primary key can't be removed.
+func (k *KeyRing) Remove(id uint32) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+	if k.hasPrimary && k.primary == id {
+		return fmt.Errorf("clockwork: key %d is primary", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+func (k *KeyRing) now() time.Time {
+	if k.Now != nil {
+		return k.Now()
+	}
+	return time.Now()
+}
+
+// Seal encrypts and authenticates payload with the primary key, and returns the token.
+// The token expires at expires. If expires is zero, it never expires.
+func (k *KeyRing) Seal(payload []byte, expires time.Time) (string, error) {
+	k.mu.RLock()
+	id, aead, ok := k.primary, k.keys[k.primary], k.hasPrimary
+	k.mu.RUnlock()
+	if !ok {
+		return "", errors.New("clockwork: no primary key")
+	}
+
+	nonceSize := aead.NonceSize()
+	buf := make([]byte, sealedHeaderSize+nonceSize, sealedHeaderSize+nonceSize+sealedExpirySize+len(payload)+aead.Overhead())
+	buf[0] = sealedVersion
+	binary.BigEndian.PutUint32(buf[1:], id)
+	nonce := buf[sealedHeaderSize:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	plaintext := make([]byte, sealedExpirySize, sealedExpirySize+len(payload))
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(plaintext, uint64(expires.Unix()))
+	}
+	plaintext = append(plaintext, payload...)
+	buf = aead.Seal(buf, nonce, plaintext, buf[:sealedHeaderSize])
+	return Base32.EncodeToString(buf), nil
+}
+
+// Open verifies and decrypts the token, and returns the payload.
+// It returns a *TokenError, which matches ErrInvalidToken, if the token is malformed or tampered,
+// ErrUnknownKey if the key is not in the key ring, and ErrTokenExpired if the token has expired.
+func (k *KeyRing) Open(token string) ([]byte, error) {
+	buf, err := Base32.DecodeString(token)
+	if err != nil {
+		return nil, &TokenError{Err: err}
+	}
+	if len(buf) < sealedHeaderSize {
+		return nil, &TokenError{Err: errors.New("too short")}
+	}
+	if buf[0] != sealedVersion {
+		return nil, &TokenError{Err: fmt.Errorf("unsupported version %d", buf[0])}
+	}
+	id := binary.BigEndian.Uint32(buf[1:])
+
+	k.mu.RLock()
+	aead, ok := k.keys[id]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(buf) < sealedHeaderSize+nonceSize+sealedExpirySize+aead.Overhead() {
+		return nil, &TokenError{Err: errors.New("too short")}
+	}
+	nonce := buf[sealedHeaderSize : sealedHeaderSize+nonceSize]
+	ciphertext := buf[sealedHeaderSize+nonceSize:]
+	plaintext, err := aead.Open(ciphertext[:0], nonce, ciphertext, buf[:sealedHeaderSize])
+	if err != nil {
+		return nil, &TokenError{Err: err}
+	}
+
+	if expires := int64(binary.BigEndian.Uint64(plaintext)); expires != 0 && k.now().Unix() >= expires {
+		return nil, ErrTokenExpired
+	}
+	return plaintext[sealedExpirySize:], nil
+}
@@ -0,0 +1,162 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestKeyRing(t *testing.T) *KeyRing {
+	t.Helper()
+	k := NewKeyRing()
+	if err := k.Add(1, bytes.Repeat([]byte{1}, 16)); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Add(2, bytes.Repeat([]byte{2}, 32)); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestKeyRing(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	k := newTestKeyRing(t)
+	k.Now = func() time.Time { return now }
+
+	token, err := k.Seal([]byte("hello"), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsQRAlphanumeric(token) {
+		t.Errorf("unexpected token: %q", token)
+	}
+	got, err := k.Open(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q, want %q", got, "hello")
+	}
+
+	// tokens are randomized
+	token2, err := k.Seal([]byte("hello"), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == token2 {
+		t.Error("tokens must differ")
+	}
+
+	// expiry
+	now = now.Add(time.Hour)
+	if _, err := k.Open(token); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// no expiry
+	token, err = k.Seal(nil, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(100 * 365 * 24 * time.Hour)
+	got, err = k.Open(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	k := newTestKeyRing(t)
+	old, err := k.Seal([]byte("old"), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := k.SetPrimary(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Remove(2); err == nil {
+		t.Error("want error for removing the primary key")
+	}
+	token, err := k.Seal([]byte("new"), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct{ token, want string }{{old, "old"}, {token, "new"}} {
+		got, err := k.Open(tt.token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+
+	if err := k.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Open(old); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := k.Open(token); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestKeyRing_Error(t *testing.T) {
+	k := NewKeyRing()
+	if _, err := k.Seal([]byte("hello"), time.Time{}); err == nil {
+		t.Error("want error without keys")
+	}
+	if err := k.Add(1, []byte("short")); err == nil {
+		t.Error("want error for invalid key size")
+	}
+	if err := k.SetPrimary(1); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	k = newTestKeyRing(t)
+	if err := k.Add(1, bytes.Repeat([]byte{3}, 16)); err == nil {
+		t.Error("want error for duplicated key id")
+	}
+	token, err := k.Seal([]byte("hello"), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := Base32.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := func(i int) string {
+		b := append([]byte(nil), raw...)
+		b[i] ^= 1
+		return Base32.EncodeToString(b)
+	}
+	tests := map[string]string{
+		"invalid symbol": "*" + token[1:],
+		"short":          Base32.EncodeToString(raw[:10]),
+		"version":        tamper(0),
+		"nonce":          tamper(5),
+		"ciphertext":     tamper(len(raw) - 20),
+		"tag":            tamper(len(raw) - 1),
+	}
+	for name, tt := range tests {
+		_, err := k.Open(tt)
+		var terr *TokenError
+		if !errors.Is(err, ErrInvalidToken) || !errors.As(err, &terr) {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	// the key id is authenticated
+	b := append([]byte(nil), raw...)
+	b[4] = 2
+	if _, err := k.Open(Base32.EncodeToString(b)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("key id: unexpected error: %v", err)
+	}
+}
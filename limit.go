@@ -0,0 +1,65 @@
+package clockwork
+
+import "strconv"
+
+// Limits restricts the amount of data that a decoder reads and writes.
+// Zero means no limit.
+type Limits struct {
+	// MaxEncoded is the maximum number of bytes read from the underlying reader.
+	MaxEncoded int64
+
+	// MaxDecoded is the maximum number of decoded bytes.
+	MaxDecoded int64
+}
+
+// A LimitError is returned when the data exceeds a limit.
+type LimitError struct {
+	// Limit is the limit in bytes.
+	Limit int64
+
+	// Decoded indicates whether the limit is on the decoded data.
+	// Otherwise, it is on the encoded data.
+	Decoded bool
+}
+
+func (e *LimitError) Error() string {
+	if e.Decoded {
+		return "clockwork: decoded data exceeds the limit of " + strconv.FormatInt(e.Limit, 10) + " bytes"
+	}
+	return "clockwork: encoded data exceeds the limit of " + strconv.FormatInt(e.Limit, 10) + " bytes"
+}
+
+// DecodeStringLimit is like DecodeString but returns a *LimitError
+// if the decoded data would exceed max bytes.
+// The check is done before decoding,
+// so large inputs are rejected without allocation.
+func (enc *Encoding) DecodeStringLimit(s string, max int) ([]byte, error) {
+	if enc.garbage == garbageAbort {
+		if enc.DecodedLen(len(s)) > max {
+			return nil, &LimitError{Limit: int64(max), Decoded: true}
+		}
+	} else if !enc.symbolsWithin(s, max) {
+		return nil, &LimitError{Limit: int64(max), Decoded: true}
+	}
+	buf, err := enc.DecodeString(s)
+	if len(buf) > max {
+		return nil, &LimitError{Limit: int64(max), Decoded: true}
+	}
+	return buf, err
+}
+
+// symbolsWithin reports whether the symbols in s decode to at most max bytes.
+// It stops scanning as soon as the symbols exceed the limit.
+func (enc *Encoding) symbolsWithin(s string, max int) bool {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if enc.value(s[i]) == 0xFF {
+			continue
+		}
+		n++
+		if enc.DecodedLen(n) > max {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package clockwork
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoderLimits_Encoded(t *testing.T) {
+	encoded := bigtest.encoded // 71 bytes
+
+	// within the limit
+	r := NewDecoderWithOptions(Base32, strings.NewReader(encoded), DecoderOptions{Limits: Limits{MaxEncoded: int64(len(encoded))}})
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != bigtest.plain {
+		t.Errorf("decoded %q, expected %q, actual %q\n", encoded, bigtest.plain, got)
+	}
+
+	// exceeds the limit
+	r = NewDecoderWithOptions(Base32, iotest.HalfReader(strings.NewReader(encoded)), DecoderOptions{Limits: Limits{MaxEncoded: 40}})
+	got, err = io.ReadAll(r)
+	var lerr *LimitError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("want LimitError, got %v", err)
+	}
+	if lerr.Limit != 40 || lerr.Decoded {
+		t.Errorf("unexpected error: %#v", lerr)
+	}
+	if want := bigtest.plain[:25]; string(got) != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestDecoderLimits_Decoded(t *testing.T) {
+	encoded := bigtest.encoded // 44 bytes after decoding
+
+	// within the limit
+	r := NewDecoderWithOptions(Base32, strings.NewReader(encoded), DecoderOptions{Limits: Limits{MaxDecoded: int64(len(bigtest.plain))}})
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != bigtest.plain {
+		t.Errorf("decoded %q, expected %q, actual %q\n", encoded, bigtest.plain, got)
+	}
+
+	// exceeds the limit
+	for _, max := range []int64{1, 10, 43} {
+		r := NewDecoderWithOptions(Base32, strings.NewReader(encoded), DecoderOptions{Limits: Limits{MaxDecoded: max}})
+		got, err := io.ReadAll(r)
+		var lerr *LimitError
+		if !errors.As(err, &lerr) {
+			t.Fatalf("max %d: want LimitError, got %v", max, err)
+		}
+		if lerr.Limit != max || !lerr.Decoded {
+			t.Errorf("max %d: unexpected error: %#v", max, lerr)
+		}
+		if want := bigtest.plain[:max]; string(got) != want {
+			t.Errorf("max %d: want %q, got %q", max, want, got)
+		}
+	}
+}
+
+func TestDecodeStringLimit(t *testing.T) {
+	got, err := Base32.DecodeStringLimit("CSQPYRK1E8", 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", got)
+	}
+
+	_, err = Base32.DecodeStringLimit("CSQPYRK1E8", 5)
+	var lerr *LimitError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("want LimitError, got %v", err)
+	}
+	if lerr.Error() != "clockwork: decoded data exceeds the limit of 5 bytes" {
+		t.Errorf("unexpected message: %s", lerr.Error())
+	}
+
+	// garbage doesn't count
+	enc := NewEncoding().IgnoreGarbage()
+	got, err = enc.DecodeStringLimit("CSQP\nYRK1\nE8\n", 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", got)
+	}
+	if _, err := enc.DecodeStringLimit("CSQP\nYRK1\nE8\n", 5); !errors.As(err, &lerr) {
+		t.Errorf("want LimitError, got %v", err)
+	}
+
+	// large inputs are rejected without copying them.
+	large := strings.Repeat("CSQPYRK1\n", 1<<16)
+	for _, enc := range []*Encoding{Base32, Base32.IgnoreGarbage(), Base32.CollectErrors()} {
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := enc.DecodeStringLimit(large, 5); !errors.As(err, &lerr) {
+				t.Errorf("want LimitError, got %v", err)
+			}
+		})
+		if allocs > 1 {
+			t.Errorf("got %f allocs, want at most 1", allocs)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package clockwork
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const obfuscatorRounds = 8
+
+// Obfuscator maps integers such as sequential database IDs to opaque codes and back.
+// It permutes 64-bit integers with a balanced Feistel network keyed by AES,
+// and encodes the result with Base32.EncodeUint64.
+//
+// The mapping is a bijection, so distinct integers never share a code,
+// but it only hides the order of integers; it doesn't authenticate them.
+type Obfuscator struct {
+	block cipher.Block
+}
+
+// NewObfuscator returns a new Obfuscator with the key.
+func NewObfuscator(key []byte) *Obfuscator {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		// the key size is always valid.
+		panic(err)
+	}
+	return &Obfuscator{block: block}
+}
+
+// round is the round function of the Feistel network.
+func (o *Obfuscator) round(i int, x uint32) uint32 {
+	var buf [aes.BlockSize]byte
+	buf[0] = byte(i)
+	binary.BigEndian.PutUint32(buf[1:], x)
+	o.block.Encrypt(buf[:], buf[:])
+	return binary.BigEndian.Uint32(buf[:])
+}
+
+// Permute returns the permuted value of v.
+func (o *Obfuscator) Permute(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for i := 0; i < obfuscatorRounds; i++ {
+		l, r = r, l^o.round(i, r)
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// Unpermute is the inverse of Permute.
+func (o *Obfuscator) Unpermute(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for i := obfuscatorRounds - 1; i >= 0; i-- {
+		l, r = r^o.round(i, l), l
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// Encode returns the opaque code of id.
+func (o *Obfuscator) Encode(id uint64) string {
+	return Base32.EncodeUint64(o.Permute(id))
+}
+
+// Decode returns the integer represented by the code.
+func (o *Obfuscator) Decode(code string) (uint64, error) {
+	v, err := Base32.DecodeUint64(code)
+	if err != nil {
+		return 0, err
+	}
+	return o.Unpermute(v), nil
+}
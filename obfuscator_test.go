@@ -0,0 +1,76 @@
+package clockwork
+
+import (
+	"math"
+	"testing"
+)
+
+func TestObfuscator(t *testing.T) {
+	o := NewObfuscator([]byte("secret key"))
+	seen := make(map[uint64]uint64)
+	check := func(v uint64) {
+		p := o.Permute(v)
+		if u, ok := seen[p]; ok && u != v {
+			t.Fatalf("Permute(%d) = Permute(%d) = %d", v, u, p)
+		}
+		seen[p] = v
+		if got := o.Unpermute(p); got != v {
+			t.Fatalf("Unpermute(Permute(%d)) = %d", v, got)
+		}
+
+		code := o.Encode(v)
+		if len(code) != Uint64Len {
+			t.Fatalf("Encode(%d) = %q", v, code)
+		}
+		got, err := o.Decode(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v {
+			t.Fatalf("Decode(Encode(%d)) = %d", v, got)
+		}
+	}
+
+	// sequential ids
+	for v := uint64(0); v < 100000; v++ {
+		check(v)
+	}
+	// the largest ids
+	for v := uint64(math.MaxUint64); v > math.MaxUint64-1000; v-- {
+		check(v)
+	}
+	// sparse ids
+	for v := uint64(1); v < math.MaxUint64/7; v *= 7 {
+		check(v)
+	}
+
+	// the inverse on permuted values
+	for p := uint64(0); p < 1000; p++ {
+		if got := o.Permute(o.Unpermute(p)); got != p {
+			t.Fatalf("Permute(Unpermute(%d)) = %d", p, got)
+		}
+	}
+}
+
+func TestObfuscator_Key(t *testing.T) {
+	a := NewObfuscator([]byte("key a"))
+	b := NewObfuscator([]byte("key b"))
+	same := 0
+	for v := uint64(0); v < 1000; v++ {
+		if a.Encode(v) == b.Encode(v) {
+			same++
+		}
+	}
+	if same > 0 {
+		t.Errorf("%d codes are the same for different keys", same)
+	}
+
+	// sequential ids don't look sequential.
+	if a.Encode(1) == a.Encode(0) || a.Encode(1)[:8] == a.Encode(2)[:8] {
+		t.Errorf("codes look sequential: %q, %q, %q", a.Encode(0), a.Encode(1), a.Encode(2))
+	}
+
+	if _, err := a.Decode("*"); err == nil {
+		t.Error("want error")
+	}
+}
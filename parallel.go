@@ -0,0 +1,120 @@
+package clockwork
+
+import (
+	"runtime"
+	"sync"
+)
+
+// defaultMinChunkSize is the default of ParallelOptions.MinChunkSize.
+const defaultMinChunkSize = 64 * 1024
+
+// ParallelOptions configures EncodeParallel and DecodeParallel.
+type ParallelOptions struct {
+	// Workers is the maximum number of goroutines.
+	// If zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// MinChunkSize is the minimum number of input bytes processed by a goroutine.
+	// If zero, 64 KiB is used.
+	MinChunkSize int
+}
+
+// chunkSize returns the number of input bytes processed by a goroutine.
+// It is a multiple of quantum.
+func (opts *ParallelOptions) chunkSize(n, quantum int) int {
+	workers, min := 0, 0
+	if opts != nil {
+		workers, min = opts.Workers, opts.MinChunkSize
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if min <= 0 {
+		min = defaultMinChunkSize
+	}
+
+	size := (n + workers - 1) / workers
+	if size < min {
+		size = min
+	}
+	return (size + quantum - 1) / quantum * quantum
+}
+
+// EncodeParallel is like Encode but encodes large src on multiple goroutines.
+// The input is split on 5-byte quantum boundaries, so the output is identical to Encode.
+// dst and src must not overlap.
+func (enc *Encoding) EncodeParallel(dst, src []byte, opts *ParallelOptions) {
+	size := opts.chunkSize(len(src), 5)
+	if size >= len(src) {
+		enc.Encode(dst, src)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for len(src) > 0 {
+		n := size
+		if n > len(src) {
+			n = len(src)
+		}
+		m := enc.EncodedLen(n)
+		wg.Add(1)
+		go func(dst, src []byte) {
+			defer wg.Done()
+			enc.Encode(dst, src)
+		}(dst[:m], src[:n])
+		src = src[n:]
+		dst = dst[m:]
+	}
+	wg.Wait()
+}
+
+// DecodeParallel is like Decode but decodes large src on multiple goroutines.
+// The input is split on 8-symbol quantum boundaries, so the output is identical to Decode.
+// If src contains invalid base32 data, it returns the number of bytes
+// before the first invalid symbol and CorruptInputError;
+// the rest of dst may be overwritten.
+// The encodings created by IgnoreGarbage and CollectErrors decode src on a single goroutine.
+// dst and src must not overlap.
+func (enc *Encoding) DecodeParallel(dst, src []byte, opts *ParallelOptions) (n int, err error) {
+	size := opts.chunkSize(len(src), 8)
+	if size >= len(src) || enc.garbage != garbageAbort {
+		return enc.decode(dst, src)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	chunks := (len(src) + size - 1) / size
+	results := make([]result, chunks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		in := src[i*size:]
+		if len(in) > size {
+			in = in[:size]
+		}
+		out := dst[i*size/8*5:]
+		if m := enc.DecodedLen(len(in)); len(out) > m {
+			out = out[:m]
+		}
+		wg.Add(1)
+		go func(i int, out, in []byte) {
+			defer wg.Done()
+			n, err := enc.decodeSymbols(out, in)
+			results[i] = result{n: n, err: err}
+		}(i, out, in)
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		n += r.n
+		if r.err != nil {
+			if pos, ok := r.err.(CorruptInputError); ok {
+				r.err = pos + CorruptInputError(i*size)
+			}
+			return n, r.err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,139 @@
+package clockwork
+
+import (
+	"bytes"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+var testParallelOptions = []*ParallelOptions{
+	nil,
+	{Workers: 1},
+	{Workers: 4, MinChunkSize: 1},
+	{Workers: 7, MinChunkSize: 13},
+	{Workers: 16, MinChunkSize: 100},
+}
+
+func TestEncodeParallel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 4, 5, 6, 39, 40, 41, 1000, 12345} {
+		src := make([]byte, size)
+		rnd.Read(src)
+		want := Base32.EncodeToString(src)
+		for _, opts := range testParallelOptions {
+			dst := make([]byte, Base32.EncodedLen(size))
+			Base32.EncodeParallel(dst, src, opts)
+			if string(dst) != want {
+				t.Errorf("size %d, options %+v: output differs from Encode", size, opts)
+			}
+		}
+	}
+}
+
+func TestDecodeParallel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 4, 5, 6, 39, 40, 41, 1000, 12345} {
+		want := make([]byte, size)
+		rnd.Read(want)
+		src := []byte(Base32.EncodeToString(want))
+		for _, opts := range testParallelOptions {
+			dst := make([]byte, Base32.DecodedLen(len(src)))
+			n, err := Base32.DecodeParallel(dst, src, opts)
+			if err != nil {
+				t.Errorf("size %d, options %+v: %v", size, opts, err)
+				continue
+			}
+			if !bytes.Equal(dst[:n], want) {
+				t.Errorf("size %d, options %+v: output differs from Decode", size, opts)
+			}
+		}
+	}
+}
+
+func TestDecodeParallel_Error(t *testing.T) {
+	src := bytes.Repeat([]byte("0"), 1000)
+	src[123] = '*'
+	src[456] = '*'
+	dst := make([]byte, Base32.DecodedLen(len(src)))
+	wantN, wantErr := Base32.Decode(dst, src)
+	for _, opts := range testParallelOptions {
+		n, err := Base32.DecodeParallel(dst, src, opts)
+		if n != wantN || err != wantErr {
+			t.Errorf("options %+v: want (%d, %v), got (%d, %v)", opts, wantN, wantErr, n, err)
+		}
+	}
+}
+
+func TestDecodeParallel_IgnoreGarbage(t *testing.T) {
+	enc := NewEncoding().IgnoreGarbage()
+	src := []byte("CSQP\nYRK1\nE8\n")
+	dst := make([]byte, enc.DecodedLen(len(src)))
+	n, err := enc.DecodeParallel(dst, src, &ParallelOptions{Workers: 4, MinChunkSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dst[:n]) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", dst[:n])
+	}
+}
+
+func TestParallel_Concurrent(t *testing.T) {
+	// EncodeParallel and DecodeParallel share the encoding and src between goroutines.
+	// Run with go test -race to check them.
+	size, rounds := 1<<16, 8
+	if raceEnabled {
+		// the race detector slows down the workers.
+		size, rounds = 1<<12, 4
+	}
+	want := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(want)
+	encoded := []byte(Base32.EncodeToString(want))
+	opts := &ParallelOptions{Workers: 4, MinChunkSize: 1}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 2*rounds)
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			dst := make([]byte, len(encoded))
+			Base32.EncodeParallel(dst, want, opts)
+			if !bytes.Equal(dst, encoded) {
+				errs <- "EncodeParallel: output differs from Encode"
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			dst := make([]byte, len(want))
+			n, err := Base32.DecodeParallel(dst, encoded, opts)
+			if err != nil || !bytes.Equal(dst[:n], want) {
+				errs <- "DecodeParallel: output differs from Decode"
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+func BenchmarkEncodeParallel(b *testing.B) {
+	data := make([]byte, 8<<20)
+	buf := make([]byte, Base32.EncodedLen(len(data)))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		Base32.EncodeParallel(buf, data, nil)
+	}
+}
+
+func BenchmarkDecodeParallel(b *testing.B) {
+	data := make([]byte, Base32.EncodedLen(8<<20))
+	Base32.Encode(data, make([]byte, 8<<20))
+	buf := make([]byte, 8<<20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		Base32.DecodeParallel(buf, data, nil)
+	}
+}
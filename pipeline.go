@@ -0,0 +1,355 @@
+package clockwork
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// defaultPipelineChunkSize is the default of PipelineOptions.ChunkSize.
+const defaultPipelineChunkSize = 64 * 1024
+
+// PipelineOptions configures NewPipelineEncoder and NewPipelineDecoder.
+type PipelineOptions struct {
+	// Workers is the number of goroutines encoding or decoding chunks.
+	// At most 2*Workers chunks are buffered.
+	// If zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// ChunkSize is the number of input bytes in a chunk.
+	// It is rounded up to a multiple of the quantum.
+	// If zero, 64 KiB is used.
+	ChunkSize int
+}
+
+func (opts *PipelineOptions) workers() int {
+	if opts == nil || opts.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return opts.Workers
+}
+
+func (opts *PipelineOptions) chunkSize(quantum int) int {
+	size := defaultPipelineChunkSize
+	if opts != nil && opts.ChunkSize > 0 {
+		size = opts.ChunkSize
+	}
+	return (size + quantum - 1) / quantum * quantum
+}
+
+// pipelineJob is a chunk processed by a worker.
+type pipelineJob struct {
+	in     []byte
+	out    []byte
+	offset int64 // offset of in in the whole input
+	n      int
+	err    error
+	done   chan struct{}
+}
+
+// runPipelineWorker processes the jobs until jobs is closed or ctx is done.
+func runPipelineWorker(ctx context.Context, jobs <-chan *pipelineJob, fn func(job *pipelineJob)) {
+	for {
+		select {
+		case job, ok := <-jobs:
+			if !ok {
+				return
+			}
+			fn(job)
+			close(job.done)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// submit sends the job to the workers and the in-order queue.
+func submitPipelineJob(ctx context.Context, job *pipelineJob, queue, jobs chan<- *pipelineJob) error {
+	select {
+	case queue <- job:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case jobs <- job:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+type pipelineEncoder struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	enc    *Encoding
+	w      io.Writer
+	size   int
+	buf    []byte // buffered data waiting to be submitted
+	jobs   chan *pipelineJob
+	queue  chan *pipelineJob
+	done   chan struct{} // closed when the writer goroutine finishes
+	closed bool
+
+	mu  sync.Mutex
+	err error // the first error
+}
+
+// NewPipelineEncoder returns a new base32 stream encoder that
+// encodes chunks on worker goroutines and writes them to w in order,
+// so reading the input, encoding and writing the output overlap.
+// The caller must Close the returned encoder to flush the last chunk
+// and to stop the goroutines.
+// After ctx is done, Write and Close return ctx.Err() without waiting for
+// a goroutine blocked in w.Write.
+func NewPipelineEncoder(ctx context.Context, enc *Encoding, w io.Writer, opts *PipelineOptions) io.WriteCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	workers := opts.workers()
+	e := &pipelineEncoder{
+		ctx:    ctx,
+		cancel: cancel,
+		enc:    enc,
+		w:      w,
+		size:   opts.chunkSize(5),
+		jobs:   make(chan *pipelineJob, workers),
+		queue:  make(chan *pipelineJob, workers),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		go runPipelineWorker(ctx, e.jobs, func(job *pipelineJob) {
+			job.out = make([]byte, enc.EncodedLen(len(job.in)))
+			enc.Encode(job.out, job.in)
+		})
+	}
+	go e.writeLoop(e.queue)
+	return e
+}
+
+func (e *pipelineEncoder) setErr(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *pipelineEncoder) getErr() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// writeLoop writes the encoded chunks in order.
+func (e *pipelineEncoder) writeLoop(queue <-chan *pipelineJob) {
+	defer close(e.done)
+	for job := range queue {
+		select {
+		case <-job.done:
+		case <-e.ctx.Done():
+			e.setErr(e.ctx.Err())
+			return
+		}
+		if _, err := e.w.Write(job.out); err != nil {
+			e.setErr(err)
+			e.cancel()
+			return
+		}
+	}
+}
+
+// Write buffers p and submits the full chunks to the workers.
+func (e *pipelineEncoder) Write(p []byte) (n int, err error) {
+	if err := e.ctx.Err(); err != nil {
+		e.setErr(err)
+	}
+	if err := e.getErr(); err != nil {
+		return 0, err
+	}
+	for len(p) > 0 {
+		if e.buf == nil {
+			e.buf = make([]byte, 0, e.size)
+		}
+		nn := e.size - len(e.buf)
+		if nn > len(p) {
+			nn = len(p)
+		}
+		e.buf = append(e.buf, p[:nn]...)
+		n += nn
+		p = p[nn:]
+
+		if len(e.buf) == e.size {
+			if err := e.submit(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (e *pipelineEncoder) submit() error {
+	job := &pipelineJob{in: e.buf, done: make(chan struct{})}
+	e.buf = nil
+	if err := submitPipelineJob(e.ctx, job, e.queue, e.jobs); err != nil {
+		e.setErr(err)
+		return e.getErr()
+	}
+	return nil
+}
+
+// Close flushes the pending data, waits for all chunks to be written,
+// and stops the goroutines.
+func (e *pipelineEncoder) Close() error {
+	if e.closed {
+		return e.getErr()
+	}
+	e.closed = true
+	if len(e.buf) > 0 && e.getErr() == nil {
+		e.submit()
+	}
+	close(e.queue)
+	close(e.jobs)
+	select {
+	case <-e.done:
+	case <-e.ctx.Done():
+		// the writer goroutine may be blocked in w.Write.
+		e.setErr(e.ctx.Err())
+	}
+	e.cancel()
+	return e.getErr()
+}
+
+type pipelineDecoder struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	enc    *Encoding
+	r      io.Reader
+	size   int
+	jobs   chan *pipelineJob
+	queue  chan *pipelineJob
+
+	out []byte // leftover decoded output
+	err error
+}
+
+// NewPipelineDecoder returns a new base32 stream decoder that
+// reads r and decodes chunks on worker goroutines ahead of Read.
+// The caller should Close the returned decoder to stop the goroutines
+// if it doesn't read until the end.
+// After ctx is done, Read returns ctx.Err().
+// A goroutine blocked in r.Read can't be stopped until it returns.
+func NewPipelineDecoder(ctx context.Context, enc *Encoding, r io.Reader, opts *PipelineOptions) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	workers := opts.workers()
+	d := &pipelineDecoder{
+		ctx:    ctx,
+		cancel: cancel,
+		enc:    enc,
+		r:      r,
+		size:   opts.chunkSize(8),
+		jobs:   make(chan *pipelineJob, workers),
+		queue:  make(chan *pipelineJob, workers),
+	}
+	for i := 0; i < workers; i++ {
+		go runPipelineWorker(ctx, d.jobs, func(job *pipelineJob) {
+			job.out = make([]byte, enc.DecodedLen(len(job.in)))
+			job.n, job.err = enc.decodeSymbols(job.out, job.in)
+			if pos, ok := job.err.(CorruptInputError); ok {
+				job.err = pos + CorruptInputError(job.offset)
+			}
+		})
+	}
+	go d.readLoop()
+	return d
+}
+
+// readLoop reads chunks of symbols and submits them.
+func (d *pipelineDecoder) readLoop() {
+	defer close(d.queue)
+	defer close(d.jobs)
+
+	var nread int64
+	var errs CorruptInputErrors
+	for {
+		buf := make([]byte, d.size)
+		offset := nread
+		var n int
+		var err error
+		for n < len(buf) && err == nil {
+			var nn int
+			nn, err = d.r.Read(buf[n:])
+			if d.enc.garbage != garbageAbort {
+				raw := buf[n : n+nn]
+				var symbols []byte
+				symbols, errs = d.enc.stripGarbage(raw[:0], raw, nread, errs, -1)
+				nread += int64(nn)
+				nn = len(symbols)
+			} else {
+				nread += int64(nn)
+			}
+			n += nn
+		}
+
+		if n > 0 {
+			job := &pipelineJob{in: buf[:n], offset: offset, done: make(chan struct{})}
+			if submitPipelineJob(d.ctx, job, d.queue, d.jobs) != nil {
+				return
+			}
+		}
+		if err == io.EOF && len(errs) > 0 {
+			err = errs
+		}
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			// report the error after the decoded data.
+			job := &pipelineJob{err: err, done: make(chan struct{})}
+			close(job.done)
+			select {
+			case d.queue <- job:
+			case <-d.ctx.Done():
+			}
+			return
+		}
+	}
+}
+
+// Read reads the decoded data in order.
+func (d *pipelineDecoder) Read(p []byte) (n int, err error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+
+		var job *pipelineJob
+		select {
+		case j, ok := <-d.queue:
+			if !ok {
+				d.err = io.EOF
+				continue
+			}
+			job = j
+		case <-d.ctx.Done():
+			d.err = d.ctx.Err()
+			continue
+		}
+		select {
+		case <-job.done:
+		case <-d.ctx.Done():
+			d.err = d.ctx.Err()
+			continue
+		}
+		d.out = job.out[:job.n]
+		d.err = job.err
+	}
+
+	n = copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// Close stops the goroutines.
+func (d *pipelineDecoder) Close() error {
+	d.cancel()
+	return nil
+}
@@ -0,0 +1,166 @@
+package clockwork
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var testPipelineOptions = []*PipelineOptions{
+	nil,
+	{Workers: 1, ChunkSize: 1},
+	{Workers: 4, ChunkSize: 7},
+	{Workers: 3, ChunkSize: 64},
+}
+
+func TestPipelineEncoder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 5, 6, 99, 1000, 4321} {
+		src := make([]byte, size)
+		rnd.Read(src)
+		want := Base32.EncodeToString(src)
+		for _, opts := range testPipelineOptions {
+			var buf bytes.Buffer
+			w := NewPipelineEncoder(context.Background(), Base32, &buf, opts)
+			for p := src; len(p) > 0; {
+				n := rnd.Intn(100) + 1
+				if n > len(p) {
+					n = len(p)
+				}
+				if _, err := w.Write(p[:n]); err != nil {
+					t.Fatal(err)
+				}
+				p = p[n:]
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != want {
+				t.Errorf("size %d, options %+v: output differs from NewEncoder", size, opts)
+			}
+		}
+	}
+}
+
+type errWriter struct {
+	n   int // number of bytes that can be written
+	err error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, w.err
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestPipelineEncoder_WriteError(t *testing.T) {
+	wantErr := errors.New("write error")
+	w := NewPipelineEncoder(context.Background(), Base32, &errWriter{n: 100, err: wantErr}, &PipelineOptions{Workers: 2, ChunkSize: 10})
+	src := make([]byte, 1000)
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		_, err = w.Write(src)
+	}
+	if err != wantErr {
+		t.Errorf("Write: want %v, got %v", wantErr, err)
+	}
+	if err := w.Close(); err != wantErr {
+		t.Errorf("Close: want %v, got %v", wantErr, err)
+	}
+}
+
+func TestPipelineEncoder_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	w := NewPipelineEncoder(ctx, Base32, pw, &PipelineOptions{Workers: 2, ChunkSize: 5})
+
+	// the writer goroutine blocks because nobody reads the pipe.
+	if _, err := w.Write([]byte("foobar")); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		_, err = w.Write([]byte("foobar"))
+	}
+	if err != context.Canceled {
+		t.Errorf("Write: want %v, got %v", context.Canceled, err)
+	}
+	if err := w.Close(); err != context.Canceled {
+		t.Errorf("Close: want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestPipelineDecoder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 5, 6, 99, 1000, 4321} {
+		want := make([]byte, size)
+		rnd.Read(want)
+		src := Base32.EncodeToString(want)
+		for _, opts := range testPipelineOptions {
+			r := NewPipelineDecoder(context.Background(), Base32, iotest.HalfReader(strings.NewReader(src)), opts)
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("size %d, options %+v: output differs from NewDecoder", size, opts)
+			}
+			if err := r.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestPipelineDecoder_Error(t *testing.T) {
+	input := strings.Repeat("0", 1000) + "*"
+	r := NewPipelineDecoder(context.Background(), Base32, strings.NewReader(input), &PipelineOptions{Workers: 4, ChunkSize: 16})
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != CorruptInputError(1000) {
+		t.Errorf("want CorruptInputError(1000), got %v", err)
+	}
+	if len(got) != 625 {
+		t.Errorf("want 625 bytes, got %d", len(got))
+	}
+}
+
+func TestPipelineDecoder_CollectErrors(t *testing.T) {
+	enc := NewEncoding().CollectErrors()
+	r := NewPipelineDecoder(context.Background(), enc, strings.NewReader("CSQP YRK1\nE8\n"), &PipelineOptions{ChunkSize: 8})
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	var errs CorruptInputErrors
+	if !errors.As(err, &errs) || len(errs) != 3 || errs[0] != 4 || errs[1] != 9 || errs[2] != 12 {
+		t.Errorf("want [4 9 12], got %v", err)
+	}
+	if string(got) != "foobar" {
+		t.Errorf("want %q, got %q", "foobar", got)
+	}
+}
+
+func TestPipelineDecoder_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	r := NewPipelineDecoder(ctx, Base32, pr, nil)
+	defer r.Close()
+
+	cancel()
+	buf := make([]byte, 64)
+	if _, err := r.Read(buf); err != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,163 @@
+package clockwork
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// QRSeparators are the characters in the QR code alphanumeric mode
+// that are not in the Clockwork Base32 alphabet.
+// They can be used as separators in QR codes without switching to the byte mode.
+const QRSeparators = " $%*+-./:"
+
+// ErrInvalidQRSegment is returned by QRSegmenter.Join when the segments are malformed or inconsistent.
+var ErrInvalidQRSegment = errors.New("clockwork: invalid QR segment")
+
+// IsQRAlphanumeric reports whether s consists of the characters
+// in the QR code alphanumeric mode only.
+// The output of Base32 always satisfies it.
+func IsQRAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || strings.IndexByte(QRSeparators, c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// QRSegmenter splits a payload into segments that fit in multiple QR codes
+// encoded in the alphanumeric mode.
+//
+// Each segment has the form INDEX SEP TOTAL SEP CRC SEP SYMBOLS,
+// where INDEX and TOTAL are decimal numbers, CRC is the CRC-32 of the whole payload
+// in 7 Base32 symbols, and SYMBOLS is a part of the Base32 encoding of the payload.
+type QRSegmenter struct {
+	// MaxLen is the maximum number of characters in a segment.
+	// e.g. 4296 is the capacity of a version 40 QR code with the error correction level L.
+	MaxLen int
+
+	// Separator separates the fields of the header.
+	// It must be one of QRSeparators. If zero, '-' is used.
+	Separator byte
+}
+
+func (s *QRSegmenter) separator() (byte, error) {
+	if s.Separator == 0 {
+		return '-', nil
+	}
+	if strings.IndexByte(QRSeparators, s.Separator) < 0 {
+		return 0, fmt.Errorf("clockwork: invalid QR separator %q", s.Separator)
+	}
+	return s.Separator, nil
+}
+
+// Split splits payload into segments.
+func (s *QRSegmenter) Split(payload []byte) ([]string, error) {
+	sep, err := s.separator()
+	if err != nil {
+		return nil, err
+	}
+	symbols := Base32.EncodeToString(payload)
+
+	// find the number of segments.
+	// the length of the header depends on the number of digits of it.
+	var total, size int
+	for digits := 1; ; digits++ {
+		size = s.MaxLen - (2*digits + 7 + 3)
+		if size <= 0 {
+			return nil, fmt.Errorf("clockwork: QR segment length %d is too short", s.MaxLen)
+		}
+		total = (len(symbols) + size - 1) / size
+		if total == 0 {
+			total = 1
+		}
+		if len(strconv.Itoa(total)) <= digits {
+			break
+		}
+	}
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(payload))
+	crc := string(appendBits(Base32, nil, sum[:], 32))
+
+	segments := make([]string, 0, total)
+	for i := 1; i <= total; i++ {
+		n := size
+		if n > len(symbols) {
+			n = len(symbols)
+		}
+		var b strings.Builder
+		b.Grow(s.MaxLen)
+		b.WriteString(strconv.Itoa(i))
+		b.WriteByte(sep)
+		b.WriteString(strconv.Itoa(total))
+		b.WriteByte(sep)
+		b.WriteString(crc)
+		b.WriteByte(sep)
+		b.WriteString(symbols[:n])
+		segments = append(segments, b.String())
+		symbols = symbols[n:]
+	}
+	return segments, nil
+}
+
+// Join reassembles the payload from segments produced by Split.
+// The segments may be in any order.
+func (s *QRSegmenter) Join(segments []string) ([]byte, error) {
+	sep, err := s.separator()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: no segments", ErrInvalidQRSegment)
+	}
+
+	var crc string
+	parts := make([]string, len(segments))
+	found := make([]bool, len(segments))
+	for _, segment := range segments {
+		fields := strings.SplitN(segment, string(sep), 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%w: missing header", ErrInvalidQRSegment)
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil || index <= 0 {
+			return nil, fmt.Errorf("%w: invalid index %q", ErrInvalidQRSegment, fields[0])
+		}
+		total, err := strconv.Atoi(fields[1])
+		if err != nil || total != len(segments) {
+			return nil, fmt.Errorf("%w: want %d segments, got %d", ErrInvalidQRSegment, total, len(segments))
+		}
+		if index > total {
+			return nil, fmt.Errorf("%w: index %d out of range", ErrInvalidQRSegment, index)
+		}
+		if found[index-1] {
+			return nil, fmt.Errorf("%w: duplicated index %d", ErrInvalidQRSegment, index)
+		}
+		if crc == "" {
+			crc = fields[2]
+		} else if crc != fields[2] {
+			return nil, fmt.Errorf("%w: segments from different payloads", ErrInvalidQRSegment)
+		}
+		found[index-1] = true
+		parts[index-1] = fields[3]
+	}
+
+	sum, err := decodeBits(Base32, []byte(crc), 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid checksum: %v", ErrInvalidQRSegment, err)
+	}
+	payload, err := Base32.DecodeString(strings.Join(parts, ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQRSegment, err)
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidQRSegment)
+	}
+	return payload, nil
+}
@@ -0,0 +1,118 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsQRAlphanumeric(t *testing.T) {
+	var all []byte
+	for i := 0; i < 256; i++ {
+		all = append(all, byte(i))
+	}
+	if s := Base32.EncodeToString(all); !IsQRAlphanumeric(s) {
+		t.Errorf("%q is not QR alphanumeric", s)
+	}
+	if !IsQRAlphanumeric("HELLO WORLD $%*+-./:") {
+		t.Error("want true")
+	}
+	for _, s := range []string{"hello", "A_B", "A#B", "A\x00"} {
+		if IsQRAlphanumeric(s) {
+			t.Errorf("IsQRAlphanumeric(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestQRSegmenter(t *testing.T) {
+	payload := make([]byte, 1000)
+	for i := range payload {
+		payload[i] = byte(i * 13)
+	}
+	for _, maxLen := range []int{20, 21, 30, 100, 1600, 4296} {
+		for _, sep := range []byte{0, ' ', '$', '%', '*', '+', '-', '.', '/', ':'} {
+			s := &QRSegmenter{MaxLen: maxLen, Separator: sep}
+			segments, err := s.Split(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, segment := range segments {
+				if len(segment) > maxLen {
+					t.Errorf("MaxLen %d: segment too long: %d", maxLen, len(segment))
+				}
+				if !IsQRAlphanumeric(segment) {
+					t.Errorf("MaxLen %d: %q is not QR alphanumeric", maxLen, segment)
+				}
+			}
+
+			// reverse the order of the segments
+			for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+				segments[i], segments[j] = segments[j], segments[i]
+			}
+			got, err := s.Join(segments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Errorf("MaxLen %d: payload mismatch", maxLen)
+			}
+		}
+	}
+}
+
+func TestQRSegmenter_Small(t *testing.T) {
+	s := &QRSegmenter{MaxLen: 100}
+	segments, err := s.Split([]byte("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 1 || !strings.HasPrefix(segments[0], "1-1-") || !strings.HasSuffix(segments[0], "-CSQPYRK1E8") {
+		t.Errorf("unexpected segments: %q", segments)
+	}
+
+	segments, err = s.Split(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Join(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestQRSegmenter_Error(t *testing.T) {
+	if _, err := (&QRSegmenter{MaxLen: 12}).Split([]byte("foobar")); err == nil {
+		t.Error("want error for too short MaxLen")
+	}
+	if _, err := (&QRSegmenter{MaxLen: 100, Separator: '_'}).Split([]byte("foobar")); err == nil {
+		t.Error("want error for invalid separator")
+	}
+
+	s := &QRSegmenter{MaxLen: 20}
+	segments, err := s.Split([]byte("The quick brown fox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.Split([]byte("The quick brown dog"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"no segments": {},
+		"missing":     segments[1:],
+		"duplicated":  append([]string{segments[0]}, segments[:len(segments)-1]...),
+		"mixed":       append([]string{other[0]}, segments[1:]...),
+		"no header":   append([]string{"CSQPY"}, segments[1:]...),
+		"corrupted":   append([]string{strings.Replace(segments[0], "-AHM", "-AHN", 1)}, segments[1:]...),
+	}
+	for name, tt := range tests {
+		if _, err := s.Join(tt); !errors.Is(err, ErrInvalidQRSegment) {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
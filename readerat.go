@@ -0,0 +1,116 @@
+package clockwork
+
+import (
+	"errors"
+	"io"
+)
+
+// A ReaderAt decodes base32 encoded data at arbitrary offsets.
+// It implements io.Reader, io.ReaderAt and io.Seeker over the decoded data,
+// so it can serve HTTP range requests with http.ServeContent.
+// The encoded data must consist of the symbols in the alphabet only.
+type ReaderAt struct {
+	enc  *Encoding
+	r    io.ReaderAt
+	size int64 // size of the encoded data
+	off  int64 // offset of Read
+}
+
+// NewReaderAt returns a ReaderAt that decodes size bytes of
+// base32 encoded data in r using enc.
+func NewReaderAt(enc *Encoding, r io.ReaderAt, size int64) *ReaderAt {
+	return &ReaderAt{enc: enc, r: r, size: size}
+}
+
+// Size returns the length of the decoded data.
+func (r *ReaderAt) Size() int64 {
+	return decodedLen64(r.size)
+}
+
+// decodedLen64 is DecodedLen for int64 lengths.
+func decodedLen64(n int64) int64 {
+	return n/8*5 + n%8*5/8
+}
+
+// ReadAt implements io.ReaderAt.
+// It reads only the quanta of the encoded data that cover p.
+func (r *ReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("clockwork.ReaderAt.ReadAt: negative offset")
+	}
+	size := r.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+	if remain := size - off; int64(len(p)) > remain {
+		p = p[:remain]
+		err = io.EOF
+	}
+
+	var in [1024]byte
+	var out [1024 / 8 * 5]byte
+	for len(p) > 0 {
+		// the quanta that cover p
+		start := off / 5 * 8
+		end := (off + int64(len(p)) + 4) / 5 * 8
+		if end > start+int64(len(in)) {
+			end = start + int64(len(in))
+		}
+		if end > r.size {
+			end = r.size
+		}
+
+		nn, rerr := r.r.ReadAt(in[:end-start], start)
+		if nn < int(end-start) {
+			if rerr == nil || rerr == io.EOF {
+				rerr = io.ErrUnexpectedEOF
+			}
+			return n, rerr
+		}
+		m, derr := r.enc.decodeSymbols(out[:], in[:nn])
+		if derr != nil {
+			if pos, ok := derr.(CorruptInputError); ok {
+				derr = pos + CorruptInputError(start)
+			}
+			return n, derr
+		}
+
+		skip := int(off - start/8*5)
+		nn = copy(p, out[skip:m])
+		n += nn
+		off += int64(nn)
+		p = p[nn:]
+	}
+	return n, err
+}
+
+// Read implements io.Reader.
+func (r *ReaderAt) Read(p []byte) (n int, err error) {
+	if r.off >= r.Size() {
+		return 0, io.EOF
+	}
+	n, err = r.ReadAt(p, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *ReaderAt) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.Size()
+	default:
+		return 0, errors.New("clockwork.ReaderAt.Seek: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("clockwork.ReaderAt.Seek: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
@@ -0,0 +1,100 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderAt(t *testing.T) {
+	data := make([]byte, 3001)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	encoded := Base32.EncodeToString(data)
+	r := NewReaderAt(Base32, strings.NewReader(encoded), int64(len(encoded)))
+	if got := r.Size(); got != int64(len(data)) {
+		t.Fatalf("Size() = %d, want %d", got, len(data))
+	}
+
+	for _, off := range []int{0, 1, 4, 5, 6, 639, 640, 641, 1234, 2995, 3000} {
+		for _, n := range []int{1, 3, 5, 8, 640, 1000, 4000} {
+			buf := make([]byte, n)
+			got, err := r.ReadAt(buf, int64(off))
+			want := n
+			if off+n > len(data) {
+				want = len(data) - off
+				if err != io.EOF {
+					t.Errorf("ReadAt(%d, %d): err = %v, want io.EOF", n, off, err)
+				}
+			} else if err != nil {
+				t.Errorf("ReadAt(%d, %d): unexpected error: %v", n, off, err)
+			}
+			if got != want {
+				t.Errorf("ReadAt(%d, %d): n = %d, want %d", n, off, got, want)
+				continue
+			}
+			if !bytes.Equal(buf[:got], data[off:off+got]) {
+				t.Errorf("ReadAt(%d, %d): unexpected data", n, off)
+			}
+		}
+	}
+
+	if _, err := r.ReadAt(make([]byte, 1), int64(len(data))); err != io.EOF {
+		t.Errorf("ReadAt at end: err = %v, want io.EOF", err)
+	}
+	if _, err := r.ReadAt(make([]byte, 1), -1); err == nil {
+		t.Error("ReadAt at negative offset: want error")
+	}
+}
+
+func TestReaderAt_Reader(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog.")
+	encoded := Base32.EncodeToString(data)
+	r := NewReaderAt(Base32, strings.NewReader(encoded), int64(len(encoded)))
+	if err := iotest.TestReader(r, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReaderAt_Seek(t *testing.T) {
+	data := []byte("foobar")
+	encoded := Base32.EncodeToString(data)
+	r := NewReaderAt(Base32, strings.NewReader(encoded), int64(len(encoded)))
+
+	if pos, err := r.Seek(-3, io.SeekEnd); err != nil || pos != 3 {
+		t.Fatalf("Seek(-3, SeekEnd) = %d, %v", pos, err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "bar" {
+		t.Errorf("got %q, want %q", got, "bar")
+	}
+	if pos, err := r.Seek(-5, io.SeekCurrent); err != nil || pos != 1 {
+		t.Fatalf("Seek(-5, SeekCurrent) = %d, %v", pos, err)
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Error("Seek(-1, SeekStart): want error")
+	}
+}
+
+func TestReaderAt_Error(t *testing.T) {
+	encoded := strings.Repeat("CSQPYRK1", 200) + "CSQ*YRK1"
+	r := NewReaderAt(Base32, strings.NewReader(encoded), int64(len(encoded)))
+	_, err := r.ReadAt(make([]byte, 5), 1000)
+	var cerr CorruptInputError
+	if !errors.As(err, &cerr) || cerr != 1603 {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the encoded data is shorter than its declared size.
+	r = NewReaderAt(Base32, strings.NewReader("CSQPYRK1"), 16)
+	if _, err := r.ReadAt(make([]byte, 10), 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package clockwork
+
+import "io"
+
+// SegmentTerminator terminates a partial quantum written by Flush
+// of the encoders returned by NewSegmentEncoder.
+const SegmentTerminator = '='
+
+// A WriteFlushCloser is an io.WriteCloser that can flush buffered data.
+type WriteFlushCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type segmentEncoder struct {
+	*Encoder
+}
+
+// Flush writes the encoding of all the data written so far.
+// If the data doesn't fill a quantum, Flush writes the partial quantum
+// followed by SegmentTerminator, so the next data starts a new quantum.
+func (e segmentEncoder) Flush() error {
+	if e.err == nil && e.nbuf > 0 {
+		e.enc.Encode(e.out[0:], e.buf[0:e.nbuf])
+		encodedLen := e.enc.EncodedLen(e.nbuf)
+		e.out[encodedLen] = SegmentTerminator
+		e.nbuf = 0
+		e.err = e.write(e.out[0 : encodedLen+1])
+	}
+	return e.err
+}
+
+// NewSegmentEncoder returns a new base32 stream encoder that can be flushed
+// in the middle of the stream, such as at message boundaries.
+// Use NewSegmentDecoder to decode its output.
+func NewSegmentEncoder(enc *Encoding, w io.Writer) WriteFlushCloser {
+	return segmentEncoder{&Encoder{enc: enc, w: w}}
+}
+
+// NewSegmentDecoder constructs a new base32 stream decoder that
+// restarts quanta after each SegmentTerminator.
+// It is the same as NewDecoderWithOptions with DecoderOptions.Segmented.
+// The returned reader is a *Decoder.
+func NewSegmentDecoder(enc *Encoding, r io.Reader) io.Reader {
+	return NewDecoderWithOptions(enc, r, DecoderOptions{Segmented: true})
+}
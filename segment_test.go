@@ -0,0 +1,130 @@
+package clockwork
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSegmentEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSegmentEncoder(Base32, &buf)
+	messages := []string{"f", "fo", "foo", "foob", "fooba", "foobar", ""}
+	for _, msg := range messages {
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "CR=CSQG=CSQPY=CSQPYRG=CSQPYRK1CSQPYRK1E8="
+	if buf.String() != want {
+		t.Errorf("want %q, got %q", want, buf.String())
+	}
+}
+
+func TestSegmentEncoder_Flush(t *testing.T) {
+	// the output is flushed at each message boundary.
+	var buf bytes.Buffer
+	w := NewSegmentEncoder(Base32, &buf)
+	if _, err := w.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("want empty, got %q", buf.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "CSQPY=" {
+		t.Errorf("want %q, got %q", "CSQPY=", buf.String())
+	}
+
+	// nothing to flush
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "CSQPY=" {
+		t.Errorf("want %q, got %q", "CSQPY=", buf.String())
+	}
+}
+
+func TestSegmentDecoder(t *testing.T) {
+	input := "CR=CSQG=CSQPY=CSQPYRG=CSQPYRK1CSQPYRK1E8=="
+	want := "ffofoofoobfoobafoobar"
+	readers := map[string]func(io.Reader) io.Reader{
+		"Reader":        func(r io.Reader) io.Reader { return r },
+		"OneByteReader": iotest.OneByteReader,
+		"HalfReader":    iotest.HalfReader,
+		"DataErrReader": iotest.DataErrReader,
+	}
+	for name, fn := range readers {
+		r := NewSegmentDecoder(Base32, fn(strings.NewReader(input)))
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: error while decoding %q: %v", name, input, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: want %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestSegmentDecoder_Blocking(t *testing.T) {
+	// the decoder returns a segment without waiting for the next quantum.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		w := NewSegmentEncoder(Base32, pw)
+		w.Write([]byte("foo"))
+		w.Flush()
+		// the writer blocks until the reader reads the segment.
+		w.Write([]byte("bar"))
+		w.Close()
+		pw.Close()
+	}()
+
+	r := NewSegmentDecoder(Base32, pr)
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "foo" {
+		t.Errorf("want %q, got %q", "foo", buf[:n])
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "bar" {
+		t.Errorf("want %q, got %q", "bar", rest)
+	}
+}
+
+func TestSegmentDecoder_IgnoreGarbage(t *testing.T) {
+	input := "CSQPY=\nCSQ PYR=\n"
+	r := NewSegmentDecoder(NewEncoding().IgnoreGarbage(), strings.NewReader(input))
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foofoo" {
+		t.Errorf("want %q, got %q", "foofoo", got)
+	}
+}
+
+func TestSegmentDecoder_Error(t *testing.T) {
+	r := NewSegmentDecoder(Base32, strings.NewReader("CSQPY=CS*"))
+	_, err := io.ReadAll(r)
+	if err != CorruptInputError(8) {
+		t.Errorf("want CorruptInputError(8), got %v", err)
+	}
+}
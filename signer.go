@@ -0,0 +1,82 @@
+package clockwork
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMalformedCode is returned by Signer.Verify when the code can't be decoded.
+	ErrMalformedCode = errors.New("clockwork: malformed code")
+
+	// ErrInvalidSignature is returned by Signer.Verify when the tag of the code doesn't match.
+	ErrInvalidSignature = errors.New("clockwork: invalid signature")
+)
+
+// Signer signs payloads with HMAC-SHA256 into short Base32 codes.
+// A code is the payload followed by the first TagBits bits of the HMAC,
+// packed into as few symbols as possible.
+type Signer struct {
+	key     []byte
+	tagBits int
+}
+
+// NewSigner returns a new Signer with the key and the tag length in bits.
+// tagBits must be between 16 and 256.
+func NewSigner(key []byte, tagBits int) (*Signer, error) {
+	if tagBits < 16 || tagBits > sha256.Size*8 {
+		return nil, fmt.Errorf("clockwork: invalid tag length %d", tagBits)
+	}
+	return &Signer{key: append([]byte(nil), key...), tagBits: tagBits}, nil
+}
+
+// tag appends the truncated HMAC of payload to dst.
+// The unused bits of the last byte are zero.
+func (s *Signer) tag(dst, payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+
+	// bind the tag length, so codes can't be reused with a shorter tag.
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], uint16(s.tagBits))
+	mac.Write(buf[:])
+	mac.Write(payload)
+	sum := mac.Sum(nil)
+
+	tag := sum[:(s.tagBits+7)/8]
+	if extra := len(tag)*8 - s.tagBits; extra > 0 {
+		tag[len(tag)-1] &^= 1<<uint(extra) - 1
+	}
+	return append(dst, tag...)
+}
+
+// Sign returns the code of payload.
+func (s *Signer) Sign(payload []byte) string {
+	buf := make([]byte, 0, len(payload)+(s.tagBits+7)/8)
+	buf = append(buf, payload...)
+	buf = s.tag(buf, payload)
+	return string(appendBits(Base32, nil, buf, len(payload)*8+s.tagBits))
+}
+
+// Verify verifies the code and returns its payload.
+// The tag is compared in constant time.
+func (s *Signer) Verify(code string) ([]byte, error) {
+	// find the length of the payload from the number of symbols.
+	nbits := 5 * len(code)
+	n := (nbits - s.tagBits) / 8
+	if nbits < s.tagBits || n*8+s.tagBits <= nbits-5 {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrMalformedCode, len(code))
+	}
+
+	buf, err := decodeBits(Base32, []byte(code), n*8+s.tagBits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCode, err)
+	}
+	payload, tag := buf[:n], buf[n:]
+	if !hmac.Equal(tag, s.tag(nil, payload)) {
+		return nil, ErrInvalidSignature
+	}
+	return payload, nil
+}
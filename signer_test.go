@@ -0,0 +1,87 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSigner(t *testing.T) {
+	key := []byte("secret key")
+	for tagBits := 16; tagBits <= 256; tagBits++ {
+		s, err := NewSigner(key, tagBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < 20; n++ {
+			payload := bytes.Repeat([]byte{byte(n)}, n)
+			code := s.Sign(payload)
+			if want := (n*8 + tagBits + 4) / 5; len(code) != want {
+				t.Fatalf("tagBits %d, payload %d: got %d symbols, want %d", tagBits, n, len(code), want)
+			}
+			got, err := s.Verify(code)
+			if err != nil {
+				t.Fatalf("tagBits %d, payload %d: %v", tagBits, n, err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Fatalf("tagBits %d, payload %d: got %x, want %x", tagBits, n, got, payload)
+			}
+		}
+	}
+}
+
+func TestSigner_Error(t *testing.T) {
+	for _, tagBits := range []int{-1, 0, 15, 257} {
+		if _, err := NewSigner([]byte("key"), tagBits); err == nil {
+			t.Errorf("tagBits %d: want error", tagBits)
+		}
+	}
+
+	s, err := NewSigner([]byte("secret key"), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := s.Sign([]byte("coupon-24"))
+
+	// lower case codes are accepted.
+	if _, err := s.Verify(strings.ToLower(code)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	other, err := NewSigner([]byte("other key"), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shorter, err := NewSigner([]byte("secret key"), 37)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := []byte(code)
+	forged[0] = Base32.encode[(Base32.decodeMap[forged[0]]+1)%32]
+
+	malformed := []string{
+		"",
+		"CSQ",
+		code[:len(code)-1],
+		code[:3] + "*" + code[4:],
+		code[:len(code)-1] + "Z", // non-zero padding bits
+	}
+	for _, tt := range malformed {
+		if _, err := s.Verify(tt); !errors.Is(err, ErrMalformedCode) {
+			t.Errorf("%q: unexpected error: %v", tt, err)
+		}
+	}
+	forgeries := []string{
+		string(forged),
+		other.Sign([]byte("coupon-24")),
+	}
+	for _, tt := range forgeries {
+		if _, err := s.Verify(tt); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%q: unexpected error: %v", tt, err)
+		}
+	}
+	if _, err := shorter.Verify(code); err == nil {
+		t.Error("want error for a different tag length")
+	}
+}
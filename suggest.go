@@ -0,0 +1,118 @@
+package clockwork
+
+// lookalikes maps characters to the symbols that they are often confused with.
+// The aliases O, I and L don't need to be here; they are decoded as 0 and 1.
+var lookalikes = [256]byte{
+	'U': 'V', 'u': 'V',
+	'5': 'S', 'S': '5', 's': '5',
+	'2': 'Z', 'Z': '2', 'z': '2',
+	'8': 'B', 'B': '8', 'b': '8',
+	'6': 'G', 'G': '6', 'g': '6',
+}
+
+// Suggest returns the candidates for the code s that has a check symbol
+// as its last symbol. The candidates are the canonical spellings of the codes
+// whose check symbol is valid and that differ from s by one substitution
+// or one transposition of adjacent symbols.
+// Substitutions of lookalike characters, such as V for U, come first,
+// followed by transpositions and the other substitutions.
+// If s is already valid, Suggest returns only its canonical spelling.
+func (enc *Encoding) Suggest(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	vals := make([]byte, len(s))
+	invalid := -1
+	for i := 0; i < len(s); i++ {
+		vals[i] = enc.value(s[i])
+		if vals[i] == 0xFF {
+			if invalid >= 0 {
+				// two or more errors can't be fixed.
+				return nil
+			}
+			invalid = i
+		}
+	}
+
+	var candidates []string
+	seen := map[string]bool{}
+	add := func() {
+		if !validCheckValues(vals) {
+			return
+		}
+		buf := make([]byte, len(vals))
+		for i, v := range vals {
+			buf[i] = enc.symbol(v)
+		}
+		str := string(buf)
+		if !seen[str] {
+			seen[str] = true
+			candidates = append(candidates, str)
+		}
+	}
+
+	if invalid >= 0 {
+		// the invalid character must be substituted.
+		if c := lookalikes[s[invalid]]; c != 0 {
+			vals[invalid] = enc.value(c)
+			add()
+		}
+		for v := byte(0); v < 32; v++ {
+			vals[invalid] = v
+			add()
+		}
+		return candidates
+	}
+
+	if validCheckValues(vals) {
+		add()
+		return candidates
+	}
+
+	// substitutions of lookalike characters
+	for i := range vals {
+		c := lookalikes[s[i]]
+		if c == 0 {
+			continue
+		}
+		orig := vals[i]
+		vals[i] = enc.value(c)
+		add()
+		vals[i] = orig
+	}
+
+	// adjacent transpositions
+	for i := 0; i+1 < len(vals); i++ {
+		if vals[i] == vals[i+1] {
+			continue
+		}
+		vals[i], vals[i+1] = vals[i+1], vals[i]
+		add()
+		vals[i], vals[i+1] = vals[i+1], vals[i]
+	}
+
+	// other substitutions
+	for i := range vals {
+		orig := vals[i]
+		for v := byte(0); v < 32; v++ {
+			if v == orig {
+				continue
+			}
+			vals[i] = v
+			add()
+		}
+		vals[i] = orig
+	}
+	return candidates
+}
+
+// validCheckValues reports whether the last value of vals is
+// the check value of the rest.
+func validCheckValues(vals []byte) bool {
+	var interim byte
+	for _, v := range vals {
+		interim = mul2(interim) ^ v
+	}
+	return interim == 0
+}
@@ -0,0 +1,94 @@
+package clockwork
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	enc := NewEncoding()
+	code := string(enc.AppendCheckSymbol(nil, []byte("Wow, it really works!")))
+
+	contains := func(list []string, s string) bool {
+		for _, v := range list {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	}
+
+	// a valid code
+	got := enc.Suggest(code)
+	if len(got) != 1 || got[0] != code {
+		t.Errorf("Suggest(%q) = %q, want [%q]", code, got, code)
+	}
+
+	// the canonical spelling of aliases
+	alias := "axqqeb1od5t2owk5c5p6ry9oexqq4tvk44" + code[len(code)-1:]
+	got = enc.Suggest(alias)
+	if len(got) != 1 || got[0] != code {
+		t.Errorf("Suggest(%q) = %q, want [%q]", alias, got, code)
+	}
+
+	// single substitutions
+	for i := 0; i < len(code); i++ {
+		for v := 0; v < 32; v++ {
+			c := enc.encode[v]
+			if c == code[i] {
+				continue
+			}
+			typo := code[:i] + string(c) + code[i+1:]
+			got := enc.Suggest(typo)
+			if !contains(got, code) {
+				t.Errorf("Suggest(%q) = %q, want to contain %q", typo, got, code)
+			}
+			for _, s := range got {
+				if err := enc.VerifyCheckSymbol([]byte(s)); err != nil {
+					t.Errorf("Suggest(%q) returns invalid %q", typo, s)
+				}
+			}
+		}
+	}
+
+	// adjacent transpositions
+	for i := 0; i+1 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		typo := code[:i] + code[i+1:i+2] + code[i:i+1] + code[i+2:]
+		got := enc.Suggest(typo)
+		if !contains(got, code) {
+			t.Errorf("Suggest(%q) = %q, want to contain %q", typo, got, code)
+		}
+	}
+}
+
+func TestSuggest_Lookalike(t *testing.T) {
+	enc := NewEncoding()
+	code := string(enc.AppendCheckSymbol(nil, []byte("Hello, world!")))
+	for i := 0; i < len(code); i++ {
+		var typo string
+		switch code[i] {
+		case 'V':
+			typo = code[:i] + "U" + code[i+1:]
+		case 'S':
+			typo = code[:i] + "5" + code[i+1:]
+		case '2':
+			typo = code[:i] + "Z" + code[i+1:]
+		default:
+			continue
+		}
+		got := enc.Suggest(typo)
+		if len(got) == 0 || got[0] != code {
+			t.Errorf("Suggest(%q) = %q, want %q first", typo, got, code)
+		}
+	}
+}
+
+func TestSuggest_Invalid(t *testing.T) {
+	enc := NewEncoding()
+	tests := []string{"", "**", "CS*Q*"}
+	for _, s := range tests {
+		if got := enc.Suggest(s); len(got) != 0 {
+			t.Errorf("Suggest(%q) = %q, want empty", s, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build go1.24
+// +build go1.24
+
+package clockwork
+
+import "encoding"
+
+var (
+	_ encoding.TextAppender = ID{}
+	_ encoding.TextAppender = Bytes(nil)
+
+	_ encoding.BinaryAppender = ID{}
+	_ encoding.BinaryAppender = Bytes(nil)
+)
@@ -0,0 +1,55 @@
+//go:build go1.24
+// +build go1.24
+
+package clockwork
+
+import (
+	"encoding"
+	"testing"
+)
+
+func TestAppendText_Allocs(t *testing.T) {
+	id := testUserType.New([]byte("foobar"))
+	b := Bytes("foobar")
+	buf := make([]byte, 0, 64)
+
+	tests := map[string]encoding.TextAppender{
+		"ID":    id,
+		"Bytes": b,
+	}
+	for name, v := range tests {
+		allocs := testing.AllocsPerRun(100, func() {
+			var err error
+			buf, err = v.AppendText(buf[:0])
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: got %f allocs, want 0", name, allocs)
+		}
+	}
+}
+
+func TestAppendBinary_Allocs(t *testing.T) {
+	id := testUserType.New([]byte("foobar"))
+	b := Bytes("foobar")
+	buf := make([]byte, 0, 64)
+
+	tests := map[string]encoding.BinaryAppender{
+		"ID":    id,
+		"Bytes": b,
+	}
+	for name, v := range tests {
+		allocs := testing.AllocsPerRun(100, func() {
+			var err error
+			buf, err = v.AppendBinary(buf[:0])
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: got %f allocs, want 0", name, allocs)
+		}
+	}
+}
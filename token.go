@@ -0,0 +1,182 @@
+package clockwork
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidToken is returned when a token has an invalid format.
+var ErrInvalidToken = errors.New("clockwork: invalid token")
+
+// A TokenError describes why TokenGenerator.Parse or KeyRing.Open rejected a token.
+// It matches ErrInvalidToken with errors.Is,
+// and unwraps to the cause such as CorruptInputError or ErrCheckSymbol.
+type TokenError struct {
+	Err error
+}
+
+func (e *TokenError) Error() string {
+	// the cause may be an error of this package, so drop its prefix.
+	return "clockwork: invalid token: " + strings.TrimPrefix(e.Err.Error(), "clockwork: ")
+}
+
+// Is reports whether target is ErrInvalidToken.
+func (e *TokenError) Is(target error) bool {
+	return target == ErrInvalidToken
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// constantTimeBase32 is the default encoding of tokens.
+// Tokens are secrets, so they are encoded in constant time.
+var constantTimeBase32 = NewEncoding().ConstantTime()
+
+// A TokenGenerator generates random tokens encoded in Clockwork Base32.
+type TokenGenerator struct {
+	// Bits is the entropy of a token in bits.
+	Bits int
+
+	// Prefix is prepended to tokens, e.g. "sk_".
+	Prefix string
+
+	// CheckSymbol indicates whether a check symbol is appended to tokens.
+	CheckSymbol bool
+
+	// Encoding is the encoding of tokens.
+	// If nil, Base32 in constant time mode is used.
+	Encoding *Encoding
+
+	// Rand is the source of randomness.
+	// If nil, crypto/rand.Reader is used.
+	Rand io.Reader
+}
+
+// NewToken returns a random token with the given entropy in bits.
+func NewToken(bits int) (string, error) {
+	g := &TokenGenerator{Bits: bits}
+	return g.New()
+}
+
+func (g *TokenGenerator) encoding() *Encoding {
+	if g.Encoding != nil {
+		return g.Encoding
+	}
+	return constantTimeBase32
+}
+
+func (g *TokenGenerator) rand() io.Reader {
+	if g.Rand != nil {
+		return g.Rand
+	}
+	return rand.Reader
+}
+
+// Len returns the length in bytes of the tokens generated by g.
+func (g *TokenGenerator) Len() int {
+	n := len(g.Prefix) + (g.Bits+4)/5
+	if g.CheckSymbol {
+		n++
+	}
+	return n
+}
+
+// New returns a new random token.
+func (g *TokenGenerator) New() (string, error) {
+	if g.Bits <= 0 {
+		return "", errors.New("clockwork: token bits must be positive")
+	}
+	enc := g.encoding()
+
+	src := make([]byte, (g.Bits+7)/8)
+	if _, err := io.ReadFull(g.rand(), src); err != nil {
+		return "", err
+	}
+	if r := g.Bits % 8; r != 0 {
+		src[len(src)-1] &= 0xFF << (8 - r)
+	}
+
+	buf := make([]byte, 0, g.Len())
+	buf = append(buf, g.Prefix...)
+	buf = appendBits(enc, buf, src, g.Bits)
+	if g.CheckSymbol {
+		interim, _ := enc.checksum(buf[len(g.Prefix):])
+		buf = append(buf, enc.symbol(mul2(interim)))
+	}
+	return string(buf), nil
+}
+
+// Parse validates the format of the token and returns its random bits.
+// The bits are packed into bytes in big-endian order,
+// and the unused bits of the last byte are zero.
+func (g *TokenGenerator) Parse(token string) ([]byte, error) {
+	if g.Bits <= 0 {
+		return nil, &TokenError{Err: errors.New("token bits must be positive")}
+	}
+	enc := g.encoding()
+
+	if !strings.HasPrefix(token, g.Prefix) {
+		return nil, &TokenError{Err: fmt.Errorf("missing prefix %q", g.Prefix)}
+	}
+	if len(token) != g.Len() {
+		return nil, &TokenError{Err: fmt.Errorf("want %d bytes, got %d", g.Len(), len(token))}
+	}
+	body := []byte(token[len(g.Prefix):])
+	if g.CheckSymbol {
+		if err := enc.VerifyCheckSymbol(body); err != nil {
+			if err, ok := err.(CorruptInputError); ok {
+				return nil, &TokenError{Err: err + CorruptInputError(len(g.Prefix))}
+			}
+			return nil, &TokenError{Err: err}
+		}
+		body = body[:len(body)-1]
+	}
+	src, err := decodeBits(enc, body, g.Bits)
+	if err != nil {
+		if err, ok := err.(CorruptInputError); ok {
+			return nil, &TokenError{Err: err + CorruptInputError(len(g.Prefix))}
+		}
+		return nil, &TokenError{Err: err}
+	}
+	return src, nil
+}
+
+// appendBits appends the first nbits bits of src to dst.
+// The bits are encoded into (nbits+4)/5 symbols,
+// so the bits of src after the first nbits bits must be zero.
+func appendBits(enc *Encoding, dst, src []byte, nbits int) []byte {
+	n := len(dst) + (nbits+4)/5
+	dst = enc.AppendEncode(dst, src)
+	return dst[:n]
+}
+
+// decodeBits is the inverse of appendBits.
+// It returns the nbits bits of src packed into (nbits+7)/8 bytes.
+func decodeBits(enc *Encoding, src []byte, nbits int) ([]byte, error) {
+	if len(src) != (nbits+4)/5 {
+		return nil, fmt.Errorf("want %d symbols, got %d", (nbits+4)/5, len(src))
+	}
+
+	// fill the symbols up to whole bytes with zeros.
+	size := (nbits + 7) / 8
+	buf := make([]byte, enc.EncodedLen(size))
+	n := copy(buf, src)
+	for i := n; i < len(buf); i++ {
+		buf[i] = '0'
+	}
+	if _, err := enc.decode(buf, buf); err != nil {
+		return nil, err
+	}
+
+	// the unused bits of the last symbol must be zero.
+	if extra := 5*len(src) - nbits; extra > 0 {
+		if enc.value(src[len(src)-1])&(1<<uint(extra)-1) != 0 {
+			return nil, errors.New("non-zero padding bits")
+		}
+	}
+	return buf[:size], nil
+}
@@ -0,0 +1,145 @@
+package clockwork
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewToken(t *testing.T) {
+	token, err := NewToken(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 26 {
+		t.Errorf("unexpected token length: want 26, got %d", len(token))
+	}
+	if _, err := Base32.DecodeString(token); err != nil {
+		t.Errorf("error while decoding %q: %v", token, err)
+	}
+}
+
+func TestTokenGenerator(t *testing.T) {
+	for bits := 1; bits <= 130; bits++ {
+		g := &TokenGenerator{
+			Bits:        bits,
+			Prefix:      "sk_",
+			CheckSymbol: true,
+			Rand:        bytes.NewReader(bytes.Repeat([]byte{0xFF}, 32)),
+		}
+		token, err := g.New()
+		if err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		if len(token) != g.Len() {
+			t.Errorf("%d bits: unexpected length: want %d, got %d", bits, g.Len(), len(token))
+		}
+		if !strings.HasPrefix(token, "sk_") {
+			t.Errorf("%d bits: missing prefix: %q", bits, token)
+		}
+
+		src, err := g.Parse(token)
+		if err != nil {
+			t.Fatalf("%d bits: error while parsing %q: %v", bits, token, err)
+		}
+
+		// all of the random bits are one.
+		var ones int
+		for _, b := range src {
+			for ; b != 0; b &= b - 1 {
+				ones++
+			}
+		}
+		if ones != bits {
+			t.Errorf("%d bits: unexpected entropy: %x", bits, src)
+		}
+	}
+}
+
+func TestTokenGenerator_Parse(t *testing.T) {
+	g := &TokenGenerator{
+		Bits:   20,
+		Prefix: "sk_",
+		Rand:   strings.NewReader("foo"),
+	}
+	token, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "sk_CSQP" {
+		t.Errorf("unexpected token: want %q, got %q", "sk_CSQP", token)
+	}
+	src, err := g.Parse("sk_csqp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != "fo`" {
+		t.Errorf("unexpected bits: want %q, got %q", "fo`", src)
+	}
+}
+
+func TestTokenGenerator_ParseError(t *testing.T) {
+	g := &TokenGenerator{
+		Bits:        20,
+		Prefix:      "sk_",
+		CheckSymbol: true,
+	}
+	c, err := Base32.CheckSymbol([]byte("CSQP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := "sk_CSQP" + string(c)
+
+	if _, err := g.Parse(code); err != nil {
+		t.Fatalf("error while parsing %q: %v", code, err)
+	}
+	if _, err := g.Parse("pk_" + code[3:]); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("wrong prefix: want ErrInvalidToken, got %v", err)
+	}
+	if _, err := g.Parse(code[:len(code)-1]); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("short token: want ErrInvalidToken, got %v", err)
+	}
+	if _, err := g.Parse("sk_CSQQ" + code[7:]); !errors.Is(err, ErrInvalidToken) || !errors.Is(err, ErrCheckSymbol) {
+		t.Errorf("typo: want ErrInvalidToken and ErrCheckSymbol, got %v", err)
+	}
+	// the prefix of the cause is not repeated.
+	_, err = g.Parse("sk_CSQQ" + code[7:])
+	if want := "clockwork: invalid token: check symbol mismatch"; err == nil || err.Error() != want {
+		t.Errorf("want %q, got %v", want, err)
+	}
+	_, err = g.Parse("sk_CS*G" + code[7:])
+	var cerr CorruptInputError
+	if !errors.Is(err, ErrInvalidToken) || !errors.As(err, &cerr) || cerr != 5 {
+		t.Errorf("invalid symbol: want ErrInvalidToken and CorruptInputError(5), got %v", err)
+	}
+
+	// the padding bits must be zero.
+	g.CheckSymbol = false
+	g.Bits = 18
+	if _, err := g.Parse("sk_CSQR"); err != nil {
+		t.Errorf("error while parsing %q: %v", "sk_CSQR", err)
+	}
+	if _, err := g.Parse("sk_CSQQ"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("padding: want ErrInvalidToken, got %v", err)
+	}
+
+	// all errors are *TokenError.
+	for _, token := range []string{"pk_CSQR", "sk_CSQ", "sk_CS*R", "sk_CSQQ"} {
+		_, err := g.Parse(token)
+		var terr *TokenError
+		if !errors.As(err, &terr) {
+			t.Errorf("%q: want *TokenError, got %T", token, err)
+		}
+	}
+	var terr *TokenError
+	if _, err := (&TokenGenerator{}).Parse("sk_CSQR"); !errors.As(err, &terr) {
+		t.Errorf("zero bits: want *TokenError, got %T", err)
+	}
+}
+
+func TestTokenGenerator_InvalidBits(t *testing.T) {
+	if _, err := NewToken(0); err == nil {
+		t.Error("want error, got nil")
+	}
+}